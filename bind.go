@@ -0,0 +1,208 @@
+package lazyenv
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	locationType        = reflect.TypeOf((*time.Location)(nil))
+	urlType             = reflect.TypeOf((*url.URL)(nil))
+	macType             = reflect.TypeOf(net.HardwareAddr(nil))
+)
+
+// Bind populates the fields of the struct target points to from the default Env, see BindFrom
+func Bind(target any) error {
+	return BindFrom(Default(), target)
+}
+
+// BindFrom populates the fields of the struct target points to from the given Env.
+// Each field tagged with `env:"KEY"` is read with GetFrom, using the built-in mapper that matches the type of the field.
+// The optional tags `default:"value"`, `required:"true"` and `sep:","` (for slices) work like OrReturn, Required and SliceOf.
+// Fields without either a default or required tag are left untouched when the variable is not set.
+// Untagged struct fields are bound recursively, other untagged fields are ignored.
+// Every field is bound even if some of them fail, the returned error is Errors listing all failures
+func BindFrom(env *Env, target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot bind to %T, expected a pointer to a struct", target)
+	}
+	collector := NewCollector(env)
+	bindStruct(collector, v.Elem())
+	return collector.Err()
+}
+
+func bindStruct(collector *Collector, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key, tagged := field.Tag.Lookup("env")
+		if !tagged {
+			if field.Type.Kind() == reflect.Struct {
+				bindStruct(collector, v.Field(i))
+			}
+			continue
+		}
+		if key == "-" {
+			continue
+		}
+		collector.Add(bindField(collector.env, v.Field(i), field, key))
+	}
+}
+
+func bindField(env *Env, v reflect.Value, field reflect.StructField, key string) error {
+	separator, ok := field.Tag.Lookup("sep")
+	if !ok {
+		separator = ","
+	}
+	mapper, err := mapperFor(field.Type, separator)
+	if err != nil {
+		return fmt.Errorf("cannot bind field %s: %w", field.Name, err)
+	}
+	getDefaultValue := Optional[any]
+	if tag, ok := field.Tag.Lookup("default"); ok {
+		defaultValue, err := mapper(tag)
+		if err != nil {
+			return fmt.Errorf("invalid default value for %s: %w", key, err)
+		}
+		getDefaultValue = OrReturn(defaultValue)
+	}
+	if tag, ok := field.Tag.Lookup("required"); ok {
+		required, err := Bool(tag)
+		if err != nil {
+			return fmt.Errorf("invalid required tag for %s: %w", key, err)
+		}
+		if required {
+			getDefaultValue = Required[any]
+		}
+	}
+	value, err := GetFrom(env, key, getDefaultValue, mapper)
+	if err != nil {
+		return err
+	}
+	if value != nil {
+		v.Set(reflect.ValueOf(value))
+	}
+	return nil
+}
+
+// mapperFor returns a mapper that produces values of type t, built from the mapper that matches its kind
+func mapperFor(t reflect.Type, separator string) (Mapper[any], error) {
+	var mapper Mapper[any]
+	if secret, ok := reflect.Zero(t).Interface().(interface {
+		bindMapper(separator string) (Mapper[any], error)
+	}); ok {
+		return secret.bindMapper(separator)
+	}
+	switch {
+	case t == durationType:
+		return anyMapper(Duration), nil
+	case t == locationType:
+		return anyMapper(Location), nil
+	case t == urlType:
+		return anyMapper(URL), nil
+	case t == macType:
+		return anyMapper(MAC), nil
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return func(value string) (any, error) {
+			result := reflect.New(t)
+			err := result.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+			return result.Elem().Interface(), err
+		}, nil
+	case t.Kind() == reflect.Pointer:
+		elem, err := mapperFor(t.Elem(), separator)
+		if err != nil {
+			return nil, err
+		}
+		return func(value string) (any, error) {
+			result, err := elem(value)
+			if err != nil {
+				return nil, err
+			}
+			pointer := reflect.New(t.Elem())
+			pointer.Elem().Set(reflect.ValueOf(result))
+			return pointer.Interface(), nil
+		}, nil
+	case t.Kind() == reflect.Slice:
+		elem, err := mapperFor(t.Elem(), separator)
+		if err != nil {
+			return nil, err
+		}
+		slice := SliceOf(separator, elem)
+		return func(value string) (any, error) {
+			results, err := slice(value)
+			if err != nil {
+				return nil, err
+			}
+			result := reflect.MakeSlice(t, len(results), len(results))
+			for i, r := range results {
+				result.Index(i).Set(reflect.ValueOf(r))
+			}
+			return result.Interface(), nil
+		}, nil
+	case t.Kind() == reflect.Struct || t.Kind() == reflect.Map || t.Kind() == reflect.Array:
+		return func(value string) (any, error) {
+			result := reflect.New(t)
+			err := json.Unmarshal([]byte(value), result.Interface())
+			return result.Elem().Interface(), err
+		}, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		mapper = anyMapper(String)
+	case reflect.Bool:
+		mapper = anyMapper(Bool)
+	case reflect.Int:
+		mapper = anyMapper(Int)
+	case reflect.Int8:
+		mapper = anyMapper(Int8)
+	case reflect.Int16:
+		mapper = anyMapper(Int16)
+	case reflect.Int32:
+		mapper = anyMapper(Int32)
+	case reflect.Int64:
+		mapper = anyMapper(Int64)
+	case reflect.Uint:
+		mapper = anyMapper(Uint)
+	case reflect.Uint8:
+		mapper = anyMapper(Uint8)
+	case reflect.Uint16:
+		mapper = anyMapper(Uint16)
+	case reflect.Uint32:
+		mapper = anyMapper(Uint32)
+	case reflect.Uint64:
+		mapper = anyMapper(Uint64)
+	case reflect.Uintptr:
+		mapper = anyMapper(Uintptr)
+	case reflect.Float32:
+		mapper = anyMapper(Float32)
+	case reflect.Float64:
+		mapper = anyMapper(Float64)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+	return func(value string) (any, error) {
+		result, err := mapper(value)
+		if err != nil {
+			return nil, err
+		}
+		// converts to named types, such as `type Level string`
+		return reflect.ValueOf(result).Convert(t).Interface(), nil
+	}, nil
+}
+
+func anyMapper[T any](mapper Mapper[T]) Mapper[any] {
+	return func(value string) (any, error) {
+		return mapper(value)
+	}
+}
@@ -0,0 +1,157 @@
+package lazyenv_test
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/danielkov/lazyenv"
+)
+
+type level string
+
+type bindDatabase struct {
+	URL  string `env:"DATABASE_URL" required:"true"`
+	Pool uint8  `env:"DATABASE_POOL" default:"4"`
+}
+
+type bindConfig struct {
+	Host     string            `env:"HOST" default:"localhost"`
+	Port     int               `env:"PORT" default:"8080"`
+	Debug    bool              `env:"DEBUG"`
+	Ratio    float64           `env:"RATIO"`
+	Level    level             `env:"LEVEL" default:"info"`
+	Hosts    []string          `env:"HOSTS"`
+	Ports    []int             `env:"PORTS" sep:";"`
+	Labels   map[string]string `env:"LABELS"`
+	IP       net.IP            `env:"IP"`
+	Timeout  *int              `env:"TIMEOUT"`
+	Ignored  string            `env:"-"`
+	Kept     string            `env:"KEPT"`
+	Database bindDatabase
+	internal string
+}
+
+func TestBind(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{
+		"PORT":          "9090",
+		"DEBUG":         "true",
+		"RATIO":         "0.5",
+		"HOSTS":         "a,b",
+		"PORTS":         "1;2;3",
+		"LABELS":        `{"team":"billing"}`,
+		"IP":            "10.0.0.1",
+		"TIMEOUT":       "30",
+		"DATABASE_URL":  "postgres://localhost/app",
+		"DATABASE_POOL": "16",
+		"-":             "never read",
+	}))
+	config := bindConfig{Kept: "kept"}
+	if err := lazyenv.BindFrom(env, &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Host != "localhost" || config.Port != 9090 || !config.Debug || config.Ratio != 0.5 || config.Level != "info" {
+		t.Errorf("unexpected scalar fields: %+v", config)
+	}
+	if len(config.Hosts) != 2 || config.Hosts[1] != "b" {
+		t.Errorf("expected [a b], got %v", config.Hosts)
+	}
+	if len(config.Ports) != 3 || config.Ports[2] != 3 {
+		t.Errorf("expected [1 2 3], got %v", config.Ports)
+	}
+	if config.Labels["team"] != "billing" {
+		t.Errorf("expected team=billing, got %v", config.Labels)
+	}
+	if !config.IP.Equal(net.IPv4(10, 0, 0, 1)) {
+		t.Errorf("expected 10.0.0.1, got %s", config.IP)
+	}
+	if config.Timeout == nil || *config.Timeout != 30 {
+		t.Errorf("expected 30, got %v", config.Timeout)
+	}
+	if config.Ignored != "" || config.Kept != "kept" {
+		t.Errorf("expected ignored and untouched fields to be kept, got %+v", config)
+	}
+	if config.Database.URL != "postgres://localhost/app" || config.Database.Pool != 16 {
+		t.Errorf("unexpected nested struct: %+v", config.Database)
+	}
+}
+
+func TestBind_Required(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{}))
+	var config bindConfig
+	err := lazyenv.BindFrom(env, &config)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if err.Error() != "required variable not found: DATABASE_URL" {
+		t.Errorf("expected error, got %s", err)
+	}
+}
+
+func TestBind_InvalidDefault(t *testing.T) {
+	t.Parallel()
+	var config struct {
+		Port int `env:"PORT" default:"eighty"`
+	}
+	if err := lazyenv.BindFrom(lazyenv.New(lazyenv.WithSource(lazyenv.Map{})), &config); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestBind_UnsupportedType(t *testing.T) {
+	t.Parallel()
+	var config struct {
+		Callback func() `env:"CALLBACK"`
+	}
+	if err := lazyenv.BindFrom(lazyenv.New(lazyenv.WithSource(lazyenv.Map{})), &config); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestBind_NotAPointer(t *testing.T) {
+	t.Parallel()
+	if err := lazyenv.Bind(bindConfig{}); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestBind_Time(t *testing.T) {
+	t.Parallel()
+	var config struct {
+		Timeout   time.Duration   `env:"TIMEOUT" default:"30s"`
+		Intervals []time.Duration `env:"INTERVALS"`
+		Zone      *time.Location  `env:"ZONE"`
+		Since     time.Time       `env:"SINCE"`
+	}
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"INTERVALS": "1s,1m", "ZONE": "UTC", "SINCE": "2024-03-01T00:00:00Z"}))
+	if err := lazyenv.BindFrom(env, &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Timeout != 30*time.Second || len(config.Intervals) != 2 || config.Intervals[1] != time.Minute {
+		t.Errorf("unexpected durations %s %v", config.Timeout, config.Intervals)
+	}
+	if config.Zone != time.UTC || config.Since.Year() != 2024 {
+		t.Errorf("unexpected times %s %s", config.Zone, config.Since)
+	}
+}
+
+func TestBind_Net(t *testing.T) {
+	t.Parallel()
+	var config struct {
+		DatabaseURL *url.URL         `env:"DATABASE_URL"`
+		ListenAddr  lazyenv.HostPort `env:"LISTEN_ADDR" default:":8080"`
+		Proxies     []netip.Prefix   `env:"TRUSTED_PROXIES"`
+		IP          netip.Addr       `env:"IP"`
+	}
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"DATABASE_URL": "postgres://localhost/app", "TRUSTED_PROXIES": "10.0.0.0/8", "IP": "::1"}))
+	if err := lazyenv.BindFrom(env, &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.DatabaseURL.Host != "localhost" || config.ListenAddr.Port != 8080 || len(config.Proxies) != 1 || !config.IP.Is6() {
+		t.Errorf("unexpected config %+v", config)
+	}
+}
@@ -0,0 +1,89 @@
+package lazyenv
+
+import (
+	"reflect"
+	"sync"
+)
+
+type entry struct {
+	value  string
+	origin string
+}
+
+// parsedKey identifies a slot of parsed values, one for each key and mapper
+type parsedKey struct {
+	key        string
+	mapperType reflect.Type
+	mapperCode uintptr
+}
+
+// parsed is a value returned by a mapper, along with the raw value it was parsed from
+type parsed struct {
+	raw   string
+	value any
+}
+
+// cache holds the raw and parsed values of an Env. Reads, which are far more common, share a read lock
+type cache struct {
+	sync.RWMutex
+	values     map[string]entry
+	parsed     map[parsedKey]parsed
+	generation uint64
+}
+
+func newCache() *cache {
+	return &cache{values: make(map[string]entry), parsed: make(map[parsedKey]parsed)}
+}
+
+func (c *cache) get(key string) (entry, bool) {
+	c.RLock()
+	defer c.RUnlock()
+	value, exists := c.values[key]
+	return value, exists
+}
+
+func (c *cache) set(key string, value entry) {
+	c.Lock()
+	defer c.Unlock()
+	c.values[key] = value
+}
+
+func (c *cache) reset() {
+	c.Lock()
+	defer c.Unlock()
+	c.values = make(map[string]entry)
+	c.parsed = make(map[parsedKey]parsed)
+	c.generation++
+}
+
+// version returns a number that changes every time the cache is reset, so that values derived from it can be invalidated
+func (c *cache) version() uint64 {
+	c.RLock()
+	defer c.RUnlock()
+	return c.generation
+}
+
+// keyOf identifies mapper by its type and its code. Closures built by the same function share their code whatever they capture,
+// so SliceOf(",", Int) built on every call finds the value it parsed before, but shares it with SliceOf(";", Int)
+func keyOf[T any](key string, mapper Mapper[T]) parsedKey {
+	return parsedKey{key, reflect.TypeOf(mapper), reflect.ValueOf(mapper).Pointer()}
+}
+
+// getParsed returns the value mapper parsed from the same raw value of key before
+func getParsed[T any](c *cache, key string, raw string, mapper Mapper[T]) (T, bool) {
+	c.RLock()
+	defer c.RUnlock()
+	slot, exists := c.parsed[keyOf(key, mapper)]
+	if !exists || slot.raw != raw {
+		var zero T
+		return zero, false
+	}
+	return slot.value.(T), true
+}
+
+// setParsed records the value parsed by mapper from the raw value of key
+func setParsed[T any](c *cache, key string, raw string, mapper Mapper[T], value T) {
+	c.Lock()
+	defer c.Unlock()
+	c.parsed[keyOf(key, mapper)] = parsed{raw, value}
+}
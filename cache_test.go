@@ -0,0 +1,221 @@
+package lazyenv_test
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+func countingMapper(calls *int) lazyenv.Mapper[int] {
+	return func(value string) (int, error) {
+		*calls++
+		return strconv.Atoi(value)
+	}
+}
+
+func TestParsedCache(t *testing.T) {
+	t.Parallel()
+	source := lazyenv.Map{"PORT": "8080"}
+	env := lazyenv.New(lazyenv.WithSource(source), lazyenv.WithParsedCache())
+	calls := 0
+	mapper := countingMapper(&calls)
+	for i := 0; i < 3; i++ {
+		if value := lazyenv.MustGetFrom(env, "PORT", mapper); value != 8080 {
+			t.Errorf("expected 8080, got %d", value)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the value to be parsed once, got %d calls", calls)
+	}
+	env.Reset()
+	lazyenv.MustGetFrom(env, "PORT", mapper)
+	if calls != 2 {
+		t.Errorf("expected Reset to clear the parsed value, got %d calls", calls)
+	}
+	source["PORT"] = "9090"
+	env.Reset()
+	if value := lazyenv.MustGetFrom(env, "PORT", mapper); value != 9090 {
+		t.Errorf("expected 9090 after Reset, got %d", value)
+	}
+}
+
+func TestParsedCache_Keys(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"HTTP_PORT": "8080", "GRPC_PORT": "9090"}), lazyenv.WithParsedCache())
+	calls := 0
+	mapper := countingMapper(&calls)
+	for i := 0; i < 3; i++ {
+		if value := lazyenv.MustGetFrom(env, "HTTP_PORT", mapper); value != 8080 {
+			t.Errorf("expected 8080, got %d", value)
+		}
+		if value := lazyenv.MustGetFrom(env, "GRPC_PORT", mapper); value != 9090 {
+			t.Errorf("expected 9090, got %d", value)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected each key to be parsed once, got %d calls", calls)
+	}
+}
+
+func TestParsedCache_Inline(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"PORTS": "80,443"}), lazyenv.WithParsedCache())
+	calls := 0
+	for i := 0; i < 3; i++ {
+		if value := lazyenv.MustGetFrom(env, "PORTS", lazyenv.SliceOf(",", countingMapper(&calls))); len(value) != 2 || value[1] != 443 {
+			t.Errorf("expected [80 443], got %v", value)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected a mapper built on every call to be parsed once, got %d calls", calls)
+	}
+	if value := lazyenv.MustGetFrom(env, "PORTS", lazyenv.String); value != "80,443" {
+		t.Errorf("expected mappers of another type not to share the slot, got %s", value)
+	}
+}
+
+func TestParsedCache_Errors(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"PORT": "abc"}), lazyenv.WithParsedCache())
+	calls := 0
+	mapper := countingMapper(&calls)
+	for i := 0; i < 2; i++ {
+		if value, _ := lazyenv.GetFrom(env, "PORT", lazyenv.OrReturn(80), mapper); value != 80 {
+			t.Errorf("expected the default, got %d", value)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected failures not to be cached, got %d calls", calls)
+	}
+}
+
+func TestParsedCache_Disabled(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"PORT": "8080"}))
+	calls := 0
+	mapper := countingMapper(&calls)
+	lazyenv.MustGetFrom(env, "PORT", mapper)
+	lazyenv.MustGetFrom(env, "PORT", mapper)
+	if calls != 2 {
+		t.Errorf("expected the mapper to be called every time without WithParsedCache, got %d calls", calls)
+	}
+}
+
+func TestGetFrom_InlineMappers(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"LIST": "a,b;c"}))
+	for i := 0; i < 2; i++ {
+		if value := lazyenv.MustGetFrom(env, "LIST", lazyenv.SliceOf(",", lazyenv.String)); len(value) != 2 {
+			t.Errorf("expected 2 items split on commas, got %v", value)
+		}
+		if value := lazyenv.MustGetFrom(env, "LIST", lazyenv.SliceOf(";", lazyenv.String)); len(value) != 2 || value[0] != "a,b" {
+			t.Errorf("expected 2 items split on semicolons, got %v", value)
+		}
+		if value := lazyenv.MustGetFrom(env, "LIST", lazyenv.String); value != "a,b;c" {
+			t.Errorf("expected the raw value, got %s", value)
+		}
+	}
+}
+
+func TestCache_Concurrent(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"PORT": "8080", "HOSTS": "a,b,c"}), lazyenv.WithParsedCache())
+	hosts := lazyenv.SliceOf(",", lazyenv.String)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				if i == 0 && j%50 == 0 {
+					env.Reset()
+				}
+				if value := lazyenv.MustGetFrom(env, "PORT", lazyenv.Int); value != 8080 {
+					t.Errorf("expected 8080, got %d", value)
+				}
+				if value := lazyenv.MustGetFrom(env, "HOSTS", hosts); len(value) != 3 {
+					t.Errorf("expected 3 hosts, got %v", value)
+				}
+				if _, exists := env.Origin("MISSING"); exists {
+					t.Errorf("expected MISSING not to exist")
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+type payload struct {
+	Name  string            `json:"name"`
+	Items []int             `json:"items"`
+	Tags  map[string]string `json:"tags"`
+}
+
+func benchmarkSource() lazyenv.Map {
+	items := make([]string, 1000)
+	for i := range items {
+		items[i] = strconv.Itoa(i)
+	}
+	return lazyenv.Map{
+		"PAYLOAD": `{"name": "benchmark", "items": [` + strings.Join(items, ",") + `], "tags": {"a": "1", "b": "2"}}`,
+		"LIST":    strings.Join(items, ","),
+	}
+}
+
+func BenchmarkGetFrom_JSONOf_Cached(b *testing.B) {
+	env := lazyenv.New(lazyenv.WithSource(benchmarkSource()), lazyenv.WithParsedCache())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		lazyenv.MustGetFrom(env, "PAYLOAD", lazyenv.JSONOf[payload])
+	}
+}
+
+func BenchmarkGetFrom_JSONOf_Uncached(b *testing.B) {
+	env := lazyenv.New(lazyenv.WithSource(benchmarkSource()))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		lazyenv.MustGetFrom(env, "PAYLOAD", lazyenv.JSONOf[payload])
+	}
+}
+
+func BenchmarkGetFrom_SliceOf_Cached(b *testing.B) {
+	env := lazyenv.New(lazyenv.WithSource(benchmarkSource()), lazyenv.WithParsedCache())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		lazyenv.MustGetFrom(env, "LIST", lazyenv.SliceOf(",", lazyenv.Int))
+	}
+}
+
+func BenchmarkGetFrom_SliceOf_Uncached(b *testing.B) {
+	env := lazyenv.New(lazyenv.WithSource(benchmarkSource()))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		lazyenv.MustGetFrom(env, "LIST", lazyenv.SliceOf(",", lazyenv.Int))
+	}
+}
+
+func BenchmarkGetFrom_Parallel(b *testing.B) {
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"PORT": "8080"}))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			lazyenv.MustGetFrom(env, "PORT", lazyenv.Int)
+		}
+	})
+}
+
+func BenchmarkGetFrom_Parallel_Reset(b *testing.B) {
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"PORT": "8080"}))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			if i%1000 == 0 {
+				env.Reset()
+			}
+			lazyenv.MustGetFrom(env, "PORT", lazyenv.Int)
+		}
+	})
+}
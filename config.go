@@ -0,0 +1,166 @@
+package lazyenv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseJSON reads a JSON object from r and flattens it into env style keys, see Flatten. name is only used to report errors
+func ParseJSON(r io.Reader, name string, separator string) (Map, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var document map[string]any
+	if err := decoder.Decode(&document); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line := bytes.Count(content[:syntaxErr.Offset], []byte("\n")) + 1
+			return nil, &SyntaxError{name, line, syntaxErr.Error()}
+		}
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		end := int(decoder.InputOffset())
+		rest := bytes.TrimLeft(content[end:], " \t\r\n")
+		line := bytes.Count(content[:len(content)-len(rest)], []byte("\n")) + 1
+		return nil, &SyntaxError{name, line, "unexpected data after the top-level object"}
+	}
+	return flattenFile(name, document, separator)
+}
+
+// LoadJSON reads and flattens the JSON file at path
+func LoadJSON(path string, separator string) (Map, error) {
+	return loadConfig(path, separator, ParseJSON)
+}
+
+func loadConfig(path string, separator string, parse func(r io.Reader, name string, separator string) (Map, error)) (Map, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parse(file, path, separator)
+}
+
+// Flatten turns a nested document, as decoded from JSON, YAML or TOML, into env style keys.
+// Keys are upper cased, characters other than letters, digits and underscores become underscores
+// and nested keys are joined with separator, so {"database": {"pool-size": 5}} becomes DATABASE_POOL_SIZE=5 with "_".
+// Lists of scalars are joined with commas, so they can be read with SliceOf, other lists, including lists of scalars
+// that contain commas, are encoded as JSON for JSONOf.
+// Null values are left out. Two keys that map to the same name are reported as an error
+func Flatten(document map[string]any, separator string) (Map, error) {
+	f := flattener{values: make(Map), paths: make(map[string]string), separator: separator}
+	if err := f.flattenMap("", "", document); err != nil {
+		return nil, err
+	}
+	return f.values, nil
+}
+
+// flattenFile works like Flatten, but prefixes its errors with the name of the file, as the other errors of the parsers
+func flattenFile(name string, document map[string]any, separator string) (Map, error) {
+	values, err := Flatten(document, separator)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return values, nil
+}
+
+type flattener struct {
+	values    Map
+	paths     map[string]string
+	separator string
+}
+
+func (f *flattener) flattenMap(key string, path string, document map[string]any) error {
+	names := make([]string, 0, len(document))
+	for name := range document {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		childKey, childPath := normalizeKey(name), name
+		if key != "" {
+			childKey, childPath = key+f.separator+childKey, path+"."+name
+		}
+		if err := f.flatten(childKey, childPath, document[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *flattener) flatten(key string, path string, value any) error {
+	var flat string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		return f.flattenMap(key, path, v)
+	case []any:
+		scalars := make([]string, len(v))
+		for i, item := range v {
+			scalar, ok := formatScalar(item)
+			// SliceOf could not tell apart items that contain a comma, so such lists are encoded as JSON too
+			if !ok || strings.Contains(scalar, ",") {
+				encoded, err := json.Marshal(v)
+				if err != nil {
+					return fmt.Errorf("cannot encode %s: %w", path, err)
+				}
+				scalars = []string{string(encoded)}
+				break
+			}
+			scalars[i] = scalar
+		}
+		flat = strings.Join(scalars, ",")
+	default:
+		scalar, ok := formatScalar(v)
+		if !ok {
+			return fmt.Errorf("unsupported value for %s: %T", path, v)
+		}
+		flat = scalar
+	}
+	if other, exists := f.paths[key]; exists {
+		return fmt.Errorf("%s and %s both map to %s", other, path, key)
+	}
+	f.paths[key] = path
+	f.values[key] = flat
+	return nil
+}
+
+func formatScalar(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	}
+	return "", false
+}
+
+func normalizeKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_':
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,142 @@
+package lazyenv_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+func expectValues(t *testing.T, values lazyenv.Map, expected map[string]string) {
+	t.Helper()
+	for key, value := range expected {
+		if actual, ok := values[key]; !ok || actual != value {
+			t.Errorf("expected %s=%q, got %q", key, value, actual)
+		}
+	}
+	if len(values) != len(expected) {
+		t.Errorf("expected %d values, got %d: %v", len(expected), len(values), values)
+	}
+}
+
+func TestConfig_JSON(t *testing.T) {
+	t.Parallel()
+	values, err := lazyenv.ParseJSON(strings.NewReader(`{
+		"name": "app",
+		"debug": true,
+		"nothing": null,
+		"database": {"host": "localhost", "pool-size": 5, "ratio": 0.25},
+		"hosts": ["a", "b"],
+		"servers": [{"host": "a"}],
+		"empty": {}
+	}`), "config.json", "_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectValues(t, values, map[string]string{
+		"NAME":               "app",
+		"DEBUG":              "true",
+		"DATABASE_HOST":      "localhost",
+		"DATABASE_POOL_SIZE": "5",
+		"DATABASE_RATIO":     "0.25",
+		"HOSTS":              "a,b",
+		"SERVERS":            `[{"host":"a"}]`,
+	})
+}
+
+func TestConfig_JSON_Separator(t *testing.T) {
+	t.Parallel()
+	values, err := lazyenv.ParseJSON(strings.NewReader(`{"database": {"pool_size": 5}}`), "config.json", "__")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectValues(t, values, map[string]string{"DATABASE__POOL_SIZE": "5"})
+}
+
+func TestConfig_JSON_SyntaxError(t *testing.T) {
+	t.Parallel()
+	_, err := lazyenv.ParseJSON(strings.NewReader("{\n\"a\": 1,\n}"), "config.json", "_")
+	var syntaxErr *lazyenv.SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.File != "config.json" || syntaxErr.Line != 3 {
+		t.Errorf("expected a syntax error on line 3, got %v", err)
+	}
+}
+
+func TestConfig_JSON_TrailingData(t *testing.T) {
+	t.Parallel()
+	for _, input := range []string{`{"a": 1} garbage {`, "{\"a\": 1}\n\n{\"b\": 2}", `{"a": 1}}`} {
+		_, err := lazyenv.ParseJSON(strings.NewReader(input), "config.json", "_")
+		var syntaxErr *lazyenv.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%q: expected a syntax error, got %v", input, err)
+		}
+	}
+	_, err := lazyenv.ParseJSON(strings.NewReader("{\"a\": 1}\n\n{\"b\": 2}"), "config.json", "_")
+	if err == nil || err.Error() != "config.json:3: unexpected data after the top-level object" {
+		t.Errorf("expected the error on line 3, got %v", err)
+	}
+	if _, err := lazyenv.ParseJSON(strings.NewReader("{\"a\": 1}\n\n"), "config.json", "_"); err != nil {
+		t.Errorf("expected trailing whitespace to be allowed, got %v", err)
+	}
+}
+
+func TestConfig_JSON_ListWithCommas(t *testing.T) {
+	t.Parallel()
+	values, err := lazyenv.ParseJSON(strings.NewReader(`{"tags": ["a,b", "c"], "hosts": ["a", "b"]}`), "config.json", "_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectValues(t, values, map[string]string{"TAGS": `["a,b","c"]`, "HOSTS": "a,b"})
+	env := lazyenv.New(lazyenv.WithSource(values))
+	if tags := lazyenv.MustGetFrom(env, "TAGS", lazyenv.JSONOf[[]string]); len(tags) != 2 || tags[0] != "a,b" {
+		t.Errorf("expected [a,b c], got %v", tags)
+	}
+}
+
+func TestConfig_Collision(t *testing.T) {
+	t.Parallel()
+	_, err := lazyenv.Flatten(map[string]any{"a": map[string]any{"b": "1"}, "a_b": "2"}, "_")
+	if err == nil || !strings.Contains(err.Error(), "A_B") {
+		t.Errorf("expected a collision error, got %v", err)
+	}
+}
+
+func TestConfig_Collision_File(t *testing.T) {
+	t.Parallel()
+	parsers := map[string]func() (lazyenv.Map, error){
+		"config.json": func() (lazyenv.Map, error) {
+			return lazyenv.ParseJSON(strings.NewReader(`{"a": {"b": 1}, "a_b": 2}`), "config.json", "_")
+		},
+		"config.yaml": func() (lazyenv.Map, error) {
+			return lazyenv.ParseYAML(strings.NewReader("a:\n  b: 1\na_b: 2\n"), "config.yaml", "_")
+		},
+		"config.toml": func() (lazyenv.Map, error) {
+			return lazyenv.ParseTOML(strings.NewReader("a_b = 2\n[a]\nb = 1\n"), "config.toml", "_")
+		},
+	}
+	for name, parse := range parsers {
+		if _, err := parse(); err == nil || !strings.HasPrefix(err.Error(), name+": ") || !strings.Contains(err.Error(), "A_B") {
+			t.Errorf("%s: expected a collision error naming the file, got %v", name, err)
+		}
+	}
+}
+
+func TestConfig_LoadJSON(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"database": {"pool_size": 5}}`), 0o600)
+	values, err := lazyenv.LoadJSON(path, "_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Chain{lazyenv.Map{}, lazyenv.Named(path, values)}))
+	if value := lazyenv.MustGetFrom(env, "DATABASE_POOL_SIZE", lazyenv.Int); value != 5 {
+		t.Errorf("expected 5, got %d", value)
+	}
+	if origin, _ := env.Origin("DATABASE_POOL_SIZE"); origin != path {
+		t.Errorf("expected %s, got %s", path, origin)
+	}
+}
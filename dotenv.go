@@ -0,0 +1,175 @@
+package lazyenv
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// SyntaxError is returned when a .env or config file cannot be parsed
+type SyntaxError struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+}
+
+// ParseDotenv reads variables in .env format from r. name is only used to report errors.
+// It understands comments, `export` prefixes, single and double quotes, escape sequences in double quotes,
+// values spanning multiple lines inside quotes and inline comments after unquoted values
+func ParseDotenv(r io.Reader, name string) (Map, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseDotenv(name, string(content))
+}
+
+// LoadDotenv reads and parses the .env file at path
+func LoadDotenv(path string) (Map, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseDotenv(file, path)
+}
+
+// Dotenv loads the given .env files and returns a chain that reads from the process environment first,
+// then from each file in the order they are given. Origin reports the path of the file a value was read from
+func Dotenv(paths ...string) (Chain, error) {
+	chain := Chain{OS}
+	for _, path := range paths {
+		values, err := LoadDotenv(path)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, Named(path, values))
+	}
+	return chain, nil
+}
+
+func parseDotenv(name string, content string) (Map, error) {
+	values := make(Map)
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		start := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if strings.HasPrefix(line, "export") && len(line) > 6 && (line[6] == ' ' || line[6] == '\t') {
+			line = strings.TrimSpace(line[6:])
+		}
+		key, rest, found := strings.Cut(line, "=")
+		if !found {
+			return nil, &SyntaxError{name, start, "expected KEY=VALUE"}
+		}
+		key = strings.TrimSpace(key)
+		if !isDotenvKey(key) {
+			return nil, &SyntaxError{name, start, fmt.Sprintf("invalid key %q", key)}
+		}
+		trimmed := strings.TrimLeft(rest, " \t")
+		if trimmed == "" || (trimmed[0] != '"' && trimmed[0] != '\'') {
+			values[key] = strings.TrimSpace(stripInlineComment(rest))
+			continue
+		}
+		quote := trimmed[0]
+		body := trimmed[1:]
+		for {
+			end := closingQuote(body, quote)
+			if end >= 0 {
+				tail := strings.TrimSpace(body[end+1:])
+				if tail != "" && tail[0] != '#' {
+					return nil, &SyntaxError{name, i + 1, fmt.Sprintf("unexpected %q after quoted value", tail)}
+				}
+				body = body[:end]
+				break
+			}
+			i++
+			if i == len(lines) {
+				return nil, &SyntaxError{name, start, "unterminated quoted value"}
+			}
+			body += "\n" + lines[i]
+		}
+		if quote == '"' {
+			body = unescapeDotenv(body)
+		}
+		values[key] = body
+	}
+	return values, nil
+}
+
+func isDotenvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, c := range key {
+		switch {
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		case i > 0 && ((c >= '0' && c <= '9') || c == '.' || c == '-'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// stripInlineComment removes a # comment from an unquoted value, a # only starts a comment when preceded by whitespace
+func stripInlineComment(value string) string {
+	for i := 1; i < len(value); i++ {
+		if value[i] == '#' && (value[i-1] == ' ' || value[i-1] == '\t') {
+			return value[:i]
+		}
+	}
+	if strings.HasPrefix(value, "#") {
+		return ""
+	}
+	return value
+}
+
+// closingQuote returns the index of the quote ending the value or -1, backslashes escape the next character in double quotes
+func closingQuote(value string, quote byte) int {
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && quote == '"':
+			i++
+		case value[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeDotenv resolves escape sequences in a double quoted value, unknown sequences such as \$ are kept as they are
+func unescapeDotenv(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			b.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '\\', '"', '\'':
+			b.WriteByte(value[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
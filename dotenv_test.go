@@ -0,0 +1,133 @@
+package lazyenv_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+const dotenvFixture = `# a comment
+PLAIN=value
+export EXPORTED=exported
+  SPACED = spaced value
+EMPTY=
+INLINE=value # comment
+HASH=a#b
+SINGLE='single $VAR \n # not a comment'
+DOUBLE="line\nnext\t\"quoted\" \$kept"
+MULTI="first
+second"
+MULTI_SINGLE='one
+two' # trailing comment
+CRLF=windows
+`
+
+func TestDotenv_Parse(t *testing.T) {
+	values, err := lazyenv.ParseDotenv(strings.NewReader(dotenvFixture), ".env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"PLAIN":        "value",
+		"EXPORTED":     "exported",
+		"SPACED":       "spaced value",
+		"EMPTY":        "",
+		"INLINE":       "value",
+		"HASH":         "a#b",
+		"SINGLE":       `single $VAR \n # not a comment`,
+		"DOUBLE":       "line\nnext\t\"quoted\" \\$kept",
+		"MULTI":        "first\nsecond",
+		"MULTI_SINGLE": "one\ntwo",
+		"CRLF":         "windows",
+	}
+	for key, value := range expected {
+		if values[key] != value {
+			t.Errorf("expected %s=%q, got %q", key, value, values[key])
+		}
+	}
+	if len(values) != len(expected) {
+		t.Errorf("expected %d values, got %d: %v", len(expected), len(values), values)
+	}
+}
+
+func TestDotenv_Parse_CRLF(t *testing.T) {
+	values, err := lazyenv.ParseDotenv(strings.NewReader("A=1\r\nB=\"x\r\ny\"\r\n"), ".env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["A"] != "1" || values["B"] != "x\ny" {
+		t.Errorf("expected A=1 B=x\\ny, got %v", values)
+	}
+}
+
+func TestDotenv_Parse_Errors(t *testing.T) {
+	cases := []struct {
+		input string
+		line  int
+	}{
+		{"A=1\nNOT A PAIR\n", 2},
+		{"A=1\n\n1KEY=value\n", 3},
+		{"A=\"unterminated\nB=2\n", 1},
+		{"A=1\nB='x' y\n", 2},
+	}
+	for _, c := range cases {
+		_, err := lazyenv.ParseDotenv(strings.NewReader(c.input), "test.env")
+		var syntaxErr *lazyenv.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("expected SyntaxError for %q, got %v", c.input, err)
+			continue
+		}
+		if syntaxErr.File != "test.env" || syntaxErr.Line != c.line {
+			t.Errorf("expected test.env:%d, got %s", c.line, syntaxErr)
+		}
+	}
+}
+
+func TestDotenv_Source(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, ".env.local")
+	base := filepath.Join(dir, ".env")
+	os.WriteFile(local, []byte("TEST_DOTENV_NAME=local\n"), 0o600)
+	os.WriteFile(base, []byte("TEST_DOTENV_NAME=base\nTEST_DOTENV_BASE=base\nTEST_DOTENV_OS=file\n"), 0o600)
+	os.Setenv("TEST_DOTENV_OS", "os")
+	defer os.Unsetenv("TEST_DOTENV_OS")
+
+	source, err := lazyenv.Dotenv(local, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := lazyenv.New(lazyenv.WithSource(source))
+	for key, expected := range map[string]string{
+		"TEST_DOTENV_NAME": "local",
+		"TEST_DOTENV_BASE": "base",
+		"TEST_DOTENV_OS":   "os",
+	} {
+		if value := lazyenv.MustGetFrom[string](env, key); value != expected {
+			t.Errorf("expected %s=%s, got %s", key, expected, value)
+		}
+	}
+}
+
+func TestDotenv_Source_MissingFile(t *testing.T) {
+	_, err := lazyenv.Dotenv(filepath.Join(t.TempDir(), ".env"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+}
+
+func TestDotenv_Source_Origin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(path, []byte("TEST_DOTENV_ORIGIN=file\n"), 0o600)
+	source, err := lazyenv.Dotenv(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := lazyenv.New(lazyenv.WithSource(source))
+	if origin, _ := env.Origin("TEST_DOTENV_ORIGIN"); origin != path {
+		t.Errorf("expected %s, got %s", path, origin)
+	}
+}
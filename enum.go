@@ -0,0 +1,102 @@
+package lazyenv
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OneOf returns a mapper that accepts only the keys of values and returns the value they map to, such as
+// OneOf(map[string]slog.Level{"debug": slog.LevelDebug, "info": slog.LevelInfo}).
+// Any other value is reported as a *ParseError naming the key, even if the Env is not strict,
+// listing the allowed values and suggesting the closest match
+func OneOf[T any](values map[string]T) Mapper[T] {
+	return oneOf(values, false)
+}
+
+// OneOfFold works like OneOf, but compares values case-insensitively. An exact match wins over a match that differs in case
+func OneOfFold[T any](values map[string]T) Mapper[T] {
+	return oneOf(values, true)
+}
+
+func oneOf[T any](values map[string]T, fold bool) Mapper[T] {
+	allowed := make([]string, 0, len(values))
+	for key := range values {
+		allowed = append(allowed, key)
+	}
+	sort.Strings(allowed)
+	return func(value string) (T, error) {
+		if result, ok := values[value]; ok {
+			return result, nil
+		}
+		if fold {
+			for _, key := range allowed {
+				if strings.EqualFold(key, value) {
+					return values[key], nil
+				}
+			}
+		}
+		var zero T
+		message := fmt.Sprintf("invalid value %q, expected one of %s", value, strings.Join(allowed, ", "))
+		if suggestion, ok := closest(value, allowed, fold); ok {
+			message += fmt.Sprintf(" (did you mean %q?)", suggestion)
+		}
+		return zero, &strictError{errors.New(message)}
+	}
+}
+
+// closest returns the candidate with the smallest edit distance to value, if it is close enough to be a likely typo
+func closest(value string, candidates []string, fold bool) (string, bool) {
+	if fold {
+		value = strings.ToLower(value)
+	}
+	best, bestDistance := "", -1
+	for _, candidate := range candidates {
+		compared := candidate
+		if fold {
+			compared = strings.ToLower(candidate)
+		}
+		distance := editDistance(value, compared)
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	limit := len(best) / 3
+	if limit < 2 {
+		limit = 2
+	}
+	return best, bestDistance >= 0 && bestDistance <= limit && bestDistance < len(best)
+}
+
+// editDistance returns the Levenshtein distance between a and b, counted in runes
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+	return result
+}
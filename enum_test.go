@@ -0,0 +1,117 @@
+package lazyenv_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+type environment int
+
+const (
+	development environment = iota
+	staging
+	production
+)
+
+var environments = map[string]environment{
+	"development": development,
+	"staging":     staging,
+	"production":  production,
+}
+
+func TestLazyGet_OneOf(t *testing.T) {
+	os.Setenv("TEST_ONE_OF", "production")
+	defer os.Unsetenv("TEST_ONE_OF")
+	value, err := lazyenv.Get("TEST_ONE_OF", lazyenv.Required[environment], lazyenv.OneOf(environments))
+	if err != nil {
+		t.Error(err)
+	}
+	if value != production {
+		t.Errorf("expected production, got %d", value)
+	}
+}
+
+func TestLazyGet_OneOf_Invalid(t *testing.T) {
+	os.Setenv("TEST_ONE_OF_INVALID", "Production")
+	defer os.Unsetenv("TEST_ONE_OF_INVALID")
+	value, err := lazyenv.Get("TEST_ONE_OF_INVALID", lazyenv.Required[environment], lazyenv.OneOf(environments))
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+	expected := `failed to parse TEST_ONE_OF_INVALID="Production": invalid value "Production", expected one of development, production, staging (did you mean "production"?)`
+	if !errors.Is(err, lazyenv.ErrParse) || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+	if value != 0 {
+		t.Errorf("expected 0, got %d", value)
+	}
+}
+
+func TestLazyGet_OneOf_Errors(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"prodution":  `invalid value "prodution", expected one of development, production, staging (did you mean "production"?)`,
+		"stageing":   `invalid value "stageing", expected one of development, production, staging (did you mean "staging"?)`,
+		"Production": `invalid value "Production", expected one of development, production, staging (did you mean "production"?)`,
+		"test":       `invalid value "test", expected one of development, production, staging`,
+		"":           `invalid value "", expected one of development, production, staging`,
+	}
+	mapper := lazyenv.OneOf(environments)
+	for input, expected := range tests {
+		if _, err := mapper(input); err == nil || err.Error() != expected {
+			t.Errorf("%q: expected %q, got %v", input, expected, err)
+		}
+	}
+}
+
+func TestLazyGet_OneOfFold(t *testing.T) {
+	t.Parallel()
+	mapper := lazyenv.OneOfFold(map[string]string{"debug": "lower", "DEBUG": "upper", "info": "info"})
+	tests := map[string]string{"debug": "lower", "DEBUG": "upper", "Debug": "upper", "INFO": "info"}
+	for input, expected := range tests {
+		value, err := mapper(input)
+		if err != nil {
+			t.Errorf("%s: %v", input, err)
+		}
+		if value != expected {
+			t.Errorf("%s: expected %s, got %s", input, expected, value)
+		}
+	}
+	if _, err := mapper("INFOO"); err == nil || err.Error() != `invalid value "INFOO", expected one of DEBUG, debug, info (did you mean "info"?)` {
+		t.Errorf("expected a suggestion ignoring case, got %v", err)
+	}
+}
+
+func TestLazyGet_OneOf_Slice(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"ENVIRONMENTS": "staging,production", "INVALID": "staging,prod"}))
+	mapper := lazyenv.SliceOf(",", lazyenv.OneOf(environments))
+	if value := lazyenv.MustGetFrom(env, "ENVIRONMENTS", mapper); len(value) != 2 || value[1] != production {
+		t.Errorf("expected [staging production], got %v", value)
+	}
+	if _, err := lazyenv.GetFrom(env, "INVALID", lazyenv.Required[[]environment], lazyenv.Strict(mapper)); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestLazyGet_OneOfFold_Suggestion(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"LOG_LEVEL": "ifno"}))
+	mapper := lazyenv.OneOfFold(map[string]string{"debug": "debug", "info": "info"})
+	expected := `failed to parse LOG_LEVEL="ifno": invalid value "ifno", expected one of debug, info (did you mean "info"?)`
+	if _, err := lazyenv.GetFrom(env, "LOG_LEVEL", lazyenv.OrReturn("debug"), mapper); err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+	if _, err := lazyenv.GetFrom(env, "LOG_LEVEL", lazyenv.Required[string], mapper); err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+	defer func() {
+		if err, ok := recover().(error); !ok || err.Error() != expected {
+			t.Errorf("expected MustGetFrom to panic with %q, got %v", expected, err)
+		}
+	}()
+	lazyenv.MustGetFrom(env, "LOG_LEVEL", mapper)
+}
@@ -0,0 +1,336 @@
+package lazyenv
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type alias struct {
+	key        string
+	deprecated bool
+}
+
+// Deprecation describes a value that was read from a deprecated alias instead of its key
+type Deprecation struct {
+	Key    string
+	Alias  string
+	Source string
+}
+
+// Env reads values from a Source and caches them. Each Env has its own cache, so separate Envs never see each other's values.
+// An Env must be created with New, its zero value is not usable
+type Env struct {
+	source     Source
+	cache      *cache
+	strict     bool
+	fileSuffix string
+	expand     bool
+	aliases    map[string][]alias
+	deprecated func(deprecation Deprecation)
+	warned     *sync.Map
+	prefix     string
+	parsed     bool
+}
+
+// Option configures an Env created by New
+type Option func(env *Env)
+
+// WithSource makes the Env read values from the given source instead of OS
+func WithSource(source Source) Option {
+	return func(env *Env) {
+		env.source = source
+	}
+}
+
+// WithStrict makes GetFrom return a *ParseError when a mapper fails, instead of treating the value as missing
+func WithStrict() Option {
+	return func(env *Env) {
+		env.strict = true
+	}
+}
+
+// WithFileSuffix makes the Env read the value of a missing KEY from the file named by KEY+suffix, e.g. with "_FILE",
+// DB_PASSWORD is read from the path in DB_PASSWORD_FILE, as Docker and Kubernetes secrets are commonly exposed.
+// A single trailing newline is removed from the content of the file
+func WithFileSuffix(suffix string) Option {
+	return func(env *Env) {
+		env.fileSuffix = suffix
+	}
+}
+
+// WithExpansion makes the Env replace references such as ${KEY} and ${KEY:-fallback} in values with the values of other variables.
+// References are resolved through the same Env, its cache included, and references that form a cycle are reported as ErrCycle
+func WithExpansion() Option {
+	return func(env *Env) {
+		env.expand = true
+	}
+}
+
+// WithPrefix makes the Env qualify every key with prefix, so that with "BILLING_", PORT is read from BILLING_PORT
+func WithPrefix(prefix string) Option {
+	return func(env *Env) {
+		env.prefix += prefix
+	}
+}
+
+// WithParsedCache makes GetFrom remember the values returned by mappers, keyed by the key and the mapper, so that reading
+// a large JSONOf value again does not parse it again. Reset clears them along with the raw values.
+// Mappers are told apart by their type and code, not by what they capture: a key must always be read with the same
+// configuration of a mapper, such as SliceOf(",", Int), and the returned slices and maps, shared between calls, must not be modified
+func WithParsedCache() Option {
+	return func(env *Env) {
+		env.parsed = true
+	}
+}
+
+// WithAliases makes the Env read key from the given aliases, in order, when key itself is not set.
+// Like the keys given to GetFrom, key and aliases are qualified with the prefix of the Env, see WithPrefix
+func WithAliases(key string, aliases ...string) Option {
+	return func(env *Env) {
+		env.addAliases(key, aliases, false)
+	}
+}
+
+// WithDeprecated works like WithAliases, but also reports the first use of each alias to the deprecation hook
+func WithDeprecated(key string, aliases ...string) Option {
+	return func(env *Env) {
+		env.addAliases(key, aliases, true)
+	}
+}
+
+// WithDeprecationHook sets the function called once for each deprecated alias that supplies a value, see WithDeprecated.
+// By default a warning is written to the standard logger
+func WithDeprecationHook(hook func(deprecation Deprecation)) Option {
+	return func(env *Env) {
+		env.deprecated = hook
+	}
+}
+
+func (e *Env) addAliases(key string, aliases []string, deprecated bool) {
+	if e.aliases == nil {
+		e.aliases = make(map[string][]alias)
+	}
+	for _, name := range aliases {
+		e.aliases[key] = append(e.aliases[key], alias{name, deprecated})
+	}
+}
+
+// New creates an Env with an empty cache, reading from OS unless an option says otherwise
+func New(options ...Option) *Env {
+	env := &Env{
+		source: OS,
+		cache:  newCache(),
+		warned: &sync.Map{},
+	}
+	for _, option := range options {
+		option(env)
+	}
+	env.qualifyAliases()
+	return env
+}
+
+// qualifyAliases qualifies the keys and aliases given to WithAliases and WithDeprecated with the prefix of the Env,
+// as every other key, whichever order the options were given in
+func (e *Env) qualifyAliases() {
+	if e.prefix == "" || len(e.aliases) == 0 {
+		return
+	}
+	qualified := make(map[string][]alias, len(e.aliases))
+	for key, aliases := range e.aliases {
+		for _, a := range aliases {
+			qualified[e.prefix+key] = append(qualified[e.prefix+key], alias{e.prefix + a.key, a.deprecated})
+		}
+	}
+	e.aliases = qualified
+}
+
+var (
+	defaultEnv atomic.Value
+	// defaultMu serializes changes to the default Env, so that concurrent calls to SetSource and SetStrict are not lost
+	defaultMu sync.Mutex
+)
+
+func init() {
+	defaultEnv.Store(New())
+}
+
+// Default returns the Env used by Get, MustGet and Reset. It is safe to call concurrently with SetDefault
+func Default() *Env {
+	return defaultEnv.Load().(*Env)
+}
+
+// SetDefault replaces the Env used by Get, MustGet and Reset. It is safe to call concurrently with Get
+func SetDefault(env *Env) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultEnv.Store(env)
+}
+
+// updateDefault replaces the default Env with a copy changed by update
+func updateDefault(update func(env *Env)) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	env := Default().clone()
+	update(env)
+	defaultEnv.Store(env)
+}
+
+// clone returns a copy of the Env that shares its cache
+func (e *Env) clone() *Env {
+	clone := *e
+	return &clone
+}
+
+// Prefix returns a view of the Env that qualifies every key with prefix, after the prefix of the Env itself if it has one,
+// so that e.Prefix("BILLING_").Prefix("DB_") reads URL from BILLING_DB_URL. The view shares the cache of the Env.
+// Errors and default value getters report the qualified key. The aliases of the Env keep the prefix it was created with,
+// and references in expanded values are qualified keys
+func (e *Env) Prefix(prefix string) *Env {
+	env := e.clone()
+	env.prefix += prefix
+	return env
+}
+
+// Lookup returns the raw value of the given key, reading it from the cache if it has been read before.
+// It also makes an Env usable as a Source of another Env, in which case a value that cannot be read is reported as missing
+func (e *Env) Lookup(key string) (string, bool) {
+	value, exists, _ := e.lookup(key)
+	return value.value, exists
+}
+
+// TryLookup works like Lookup, but also returns the error if the value cannot be read
+func (e *Env) TryLookup(key string) (string, bool, error) {
+	value, exists, err := e.lookup(key)
+	return value.value, exists, err
+}
+
+// Origin returns the name of the source that supplied the value of the given key, such as "env" or the path of a .env file.
+// When the source is Layered, the name of the layer is returned, when the value was read from a file, its path is returned
+func (e *Env) Origin(key string) (string, bool) {
+	value, exists, _ := e.lookup(key)
+	return value.origin, exists
+}
+
+func (e *Env) lookup(key string) (entry, bool, error) {
+	return e.resolve(e.prefix+key, nil)
+}
+
+// resolve looks up key and then its aliases, stack holds the keys whose values are being expanded when key is referenced, to detect cycles
+func (e *Env) resolve(key string, stack []string) (entry, bool, error) {
+	value, _, exists, err := e.resolveAliases(key, stack)
+	return value, exists, err
+}
+
+// resolveAliases works like resolve, but also returns the keys that were tried, the last of which supplied the value if it exists
+func (e *Env) resolveAliases(key string, stack []string) (entry, []string, bool, error) {
+	keys := []string{key}
+	value, exists, err := e.resolveKey(key, stack)
+	if exists || err != nil {
+		return value, keys, exists, err
+	}
+	for _, alias := range e.aliases[key] {
+		keys = append(keys, alias.key)
+		value, exists, err = e.resolveKey(alias.key, stack)
+		if err != nil {
+			return entry{}, keys, false, err
+		}
+		if exists {
+			if alias.deprecated {
+				e.warn(Deprecation{Key: key, Alias: alias.key, Source: value.origin})
+			}
+			return value, keys, true, nil
+		}
+	}
+	return entry{}, keys, false, nil
+}
+
+// warn calls the deprecation hook, only the first time the alias is used
+func (e *Env) warn(deprecation Deprecation) {
+	if _, warned := e.warned.LoadOrStore(deprecation.Alias, true); warned {
+		return
+	}
+	if e.deprecated != nil {
+		e.deprecated(deprecation)
+		return
+	}
+	log.Printf("lazyenv: %s is deprecated, use %s instead", deprecation.Alias, deprecation.Key)
+}
+
+// resolveKey looks up key without its aliases
+func (e *Env) resolveKey(key string, stack []string) (entry, bool, error) {
+	for i, k := range stack {
+		if k == key {
+			cycle := strings.Join(append(stack[i:], key), " -> ")
+			return entry{}, false, &VarError{Key: key, Cause: fmt.Errorf("%w: %s", ErrCycle, cycle)}
+		}
+	}
+	value, exists := e.cache.get(key)
+	if exists {
+		return value, true, nil
+	}
+	value, exists, err := e.read(key)
+	if err != nil {
+		return entry{}, false, err
+	}
+	if !exists && e.fileSuffix != "" {
+		value, exists, err = e.readFile(key)
+		if err != nil {
+			return entry{}, false, err
+		}
+	}
+	if !exists {
+		return entry{}, false, nil
+	}
+	if e.expand {
+		expanded, err := expand(value.value, func(reference string) (string, bool, error) {
+			value, exists, err := e.resolve(reference, append(stack, key))
+			return value.value, exists, err
+		})
+		// Errors about references name the variable they are about, the variable being read is added around them,
+		// once for the key that was asked for rather than for each reference on the way
+		var varErr *VarError
+		if err != nil && (!errors.As(err, &varErr) || len(stack) == 0) {
+			err = &VarError{Key: key, Source: value.origin, Cause: err}
+		}
+		if err != nil {
+			return entry{}, false, err
+		}
+		value.value = expanded
+	}
+	e.cache.set(key, value)
+	return value, true, nil
+}
+
+// read reads the value of key from the source, along with the name of the layer that supplied it
+func (e *Env) read(key string) (entry, bool, error) {
+	value, source, exists, err := lookupLayer(e.source, key)
+	if err != nil {
+		return entry{}, false, &VarError{Key: key, Source: nameOf(source), Cause: err}
+	}
+	if !exists {
+		return entry{}, false, nil
+	}
+	return entry{value, nameOf(source)}, true, nil
+}
+
+// readFile reads the value of key from the file named by key+fileSuffix
+func (e *Env) readFile(key string) (entry, bool, error) {
+	path, exists, err := e.read(key + e.fileSuffix)
+	if !exists || err != nil {
+		return entry{}, false, err
+	}
+	content, err := readValueFile(path.value)
+	if err != nil {
+		return entry{}, false, &VarError{Key: key, Value: path.value, Source: path.origin, Cause: err}
+	}
+	return entry{content, path.value}, true, nil
+}
+
+// Reset clears the cache so that each call to GetFrom will fetch the value from the source again
+func (e *Env) Reset() {
+	e.cache.reset()
+}
@@ -0,0 +1,320 @@
+package lazyenv_test
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+func TestEnv_GetFrom(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"PORT": "8080"}))
+	value, err := lazyenv.GetFrom(env, "PORT", lazyenv.Required[int], lazyenv.Int)
+	if err != nil {
+		t.Error(err)
+	}
+	if value != 8080 {
+		t.Errorf("expected 8080, got %d", value)
+	}
+}
+
+func TestEnv_Isolated(t *testing.T) {
+	t.Parallel()
+	a := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"NAME": "a"}))
+	b := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"NAME": "b"}))
+	if value := lazyenv.MustGetFrom[string](a, "NAME"); value != "a" {
+		t.Errorf("expected a, got %s", value)
+	}
+	if value := lazyenv.MustGetFrom[string](b, "NAME"); value != "b" {
+		t.Errorf("expected b, got %s", value)
+	}
+	a.Reset()
+	if value := lazyenv.MustGetFrom[string](b, "NAME"); value != "b" {
+		t.Errorf("expected b after resetting a, got %s", value)
+	}
+}
+
+func TestEnv_Cache(t *testing.T) {
+	t.Parallel()
+	source := lazyenv.Map{"NAME": "first"}
+	env := lazyenv.New(lazyenv.WithSource(source))
+	lazyenv.MustGetFrom[string](env, "NAME")
+	source["NAME"] = "second"
+	if value := lazyenv.MustGetFrom[string](env, "NAME"); value != "first" {
+		t.Errorf("expected first from cache, got %s", value)
+	}
+	env.Reset()
+	if value := lazyenv.MustGetFrom[string](env, "NAME"); value != "second" {
+		t.Errorf("expected second, got %s", value)
+	}
+}
+
+func TestEnv_MustGetFrom_Panic(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected MustGetFrom to panic when no value is present")
+		}
+	}()
+	lazyenv.MustGetFrom[string](lazyenv.New(lazyenv.WithSource(lazyenv.Map{})), "MISSING")
+
+	t.Errorf("if this test made it this far, it did not panic as it was supposed to")
+}
+
+func TestEnv_AsSource(t *testing.T) {
+	t.Parallel()
+	parent := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"NAME": "parent"}))
+	child := lazyenv.New(lazyenv.WithSource(parent))
+	if value := lazyenv.MustGetFrom[string](child, "NAME"); value != "parent" {
+		t.Errorf("expected parent, got %s", value)
+	}
+}
+
+func TestEnv_Aliases(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(
+		lazyenv.WithSource(lazyenv.Map{"OLDEST_ADDR": "oldest", "OLD_PORT": "6379"}),
+		lazyenv.WithAliases("CACHE_ADDR", "OLD_ADDR", "OLDEST_ADDR"),
+		lazyenv.WithAliases("CACHE_PORT", "OLD_PORT"),
+	)
+	if value := lazyenv.MustGetFrom[string](env, "CACHE_ADDR"); value != "oldest" {
+		t.Errorf("expected oldest, got %s", value)
+	}
+	if value := lazyenv.MustGetFrom(env, "CACHE_PORT", lazyenv.Int); value != 6379 {
+		t.Errorf("expected 6379, got %d", value)
+	}
+	if value, _ := env.Lookup("CACHE_ADDR"); value != "oldest" {
+		t.Errorf("expected Lookup to use the aliases, got %s", value)
+	}
+}
+
+func TestEnv_Aliases_Prefix(t *testing.T) {
+	t.Parallel()
+	for _, options := range [][]lazyenv.Option{
+		{lazyenv.WithPrefix("APP_"), lazyenv.WithDeprecated("NEW", "OLD")},
+		{lazyenv.WithDeprecated("NEW", "OLD"), lazyenv.WithPrefix("APP_")},
+	} {
+		var deprecations []lazyenv.Deprecation
+		options = append(options,
+			lazyenv.WithSource(lazyenv.Map{"APP_OLD": "old", "OLD": "unprefixed"}),
+			lazyenv.WithDeprecationHook(func(deprecation lazyenv.Deprecation) {
+				deprecations = append(deprecations, deprecation)
+			}),
+		)
+		env := lazyenv.New(options...)
+		if value, err := lazyenv.GetFrom(env, "NEW", lazyenv.Required[string]); err != nil || value != "old" {
+			t.Errorf("expected the alias to be qualified with the prefix, got %q, %v", value, err)
+		}
+		if len(deprecations) != 1 || deprecations[0].Key != "APP_NEW" || deprecations[0].Alias != "APP_OLD" {
+			t.Errorf("expected a warning naming the qualified keys, got %v", deprecations)
+		}
+	}
+	env := lazyenv.New(lazyenv.WithPrefix("APP_"), lazyenv.WithAliases("NEW", "OLD"), lazyenv.WithSource(lazyenv.Map{}))
+	_, err := lazyenv.GetFrom(env, "NEW", lazyenv.Required[string])
+	if expected := "required variable not found: APP_NEW (also tried APP_OLD)"; err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func TestEnv_Aliases_Order(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(
+		lazyenv.WithSource(lazyenv.Map{"CACHE_ADDR": "new", "OLD_ADDR": "old", "OLDEST_ADDR": "oldest"}),
+		lazyenv.WithAliases("CACHE_ADDR", "OLD_ADDR", "OLDEST_ADDR"),
+		lazyenv.WithAliases("OTHER", "OLDEST_ADDR", "OLD_ADDR"),
+	)
+	if value := lazyenv.MustGetFrom[string](env, "CACHE_ADDR"); value != "new" {
+		t.Errorf("expected the key to win over its aliases, got %s", value)
+	}
+	if value := lazyenv.MustGetFrom[string](env, "OTHER"); value != "oldest" {
+		t.Errorf("expected the first alias to win, got %s", value)
+	}
+}
+
+func TestEnv_Aliases_Missing(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(
+		lazyenv.WithSource(lazyenv.Map{}),
+		lazyenv.WithAliases("CACHE_ADDR", "REDIS_ADDR", "REDIS_URL"),
+	)
+	var keys []string
+	lazyenv.GetFrom(env, "CACHE_ADDR", func(params lazyenv.GetDefaultValueParams) (string, error) {
+		keys = params.Keys
+		return "", nil
+	})
+	if expected := []string{"CACHE_ADDR", "REDIS_ADDR", "REDIS_URL"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v, got %v", expected, keys)
+	}
+	_, err := lazyenv.GetFrom(env, "CACHE_ADDR", lazyenv.Required[string])
+	if !errors.Is(err, lazyenv.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if expected := "required variable not found: CACHE_ADDR (also tried REDIS_ADDR, REDIS_URL)"; err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestEnv_Aliases_ParseError(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(
+		lazyenv.WithSource(lazyenv.Map{"OLD_PORT": "abc"}),
+		lazyenv.WithAliases("PORT", "OLD_PORT"),
+		lazyenv.WithStrict(),
+	)
+	_, err := lazyenv.GetFrom(env, "PORT", lazyenv.Required[int], lazyenv.Int)
+	var parseErr *lazyenv.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected a *ParseError, got %v", err)
+	}
+	if parseErr.Key != "OLD_PORT" {
+		t.Errorf("expected the error to name the alias that supplied the value, got %s", parseErr.Key)
+	}
+	if expected := []string{"PORT", "OLD_PORT"}; !reflect.DeepEqual(parseErr.Keys, expected) {
+		t.Errorf("expected %v, got %v", expected, parseErr.Keys)
+	}
+	if expected := `failed to parse OLD_PORT="abc" (tried PORT, OLD_PORT): strconv.Atoi: parsing "abc": invalid syntax`; err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestEnv_Aliases_ParseError_Required(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(
+		lazyenv.WithSource(lazyenv.Map{"OLD_PORT": "abc"}),
+		lazyenv.WithAliases("PORT", "OLD_PORT"),
+	)
+	_, err := lazyenv.GetFrom(env, "PORT", lazyenv.Required[int], lazyenv.Int)
+	var parseErr *lazyenv.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected a *ParseError, got %v", err)
+	}
+	if parseErr.Key != "OLD_PORT" || !reflect.DeepEqual(parseErr.Keys, []string{"PORT", "OLD_PORT"}) {
+		t.Errorf("expected Required to report the keys as strict mode does, got %s %v", parseErr.Key, parseErr.Keys)
+	}
+	defer func() {
+		if err, ok := recover().(*lazyenv.ParseError); !ok || err.Key != "OLD_PORT" || len(err.Keys) != 2 {
+			t.Errorf("expected MustGetFrom to report the keys as strict mode does, got %v", err)
+		}
+	}()
+	lazyenv.MustGetFrom(env, "PORT", lazyenv.Int)
+}
+
+func TestEnv_Deprecated(t *testing.T) {
+	t.Parallel()
+	var deprecations []lazyenv.Deprecation
+	env := lazyenv.New(
+		lazyenv.WithSource(lazyenv.Named("legacy", lazyenv.Map{"REDIS_ADDR": "localhost:6379"})),
+		lazyenv.WithDeprecated("CACHE_ADDR", "REDIS_ADDR"),
+		lazyenv.WithDeprecated("CACHE_URL", "REDIS_URL"),
+		lazyenv.WithDeprecationHook(func(deprecation lazyenv.Deprecation) {
+			deprecations = append(deprecations, deprecation)
+		}),
+	)
+	lazyenv.GetFrom(env, "CACHE_URL", lazyenv.Optional[string])
+	if len(deprecations) != 0 {
+		t.Errorf("expected no warning for a deprecated alias that is not set, got %v", deprecations)
+	}
+	for i := 0; i < 3; i++ {
+		if value := lazyenv.MustGetFrom[string](env, "CACHE_ADDR"); value != "localhost:6379" {
+			t.Errorf("expected localhost:6379, got %s", value)
+		}
+		env.Reset()
+	}
+	expected := []lazyenv.Deprecation{{Key: "CACHE_ADDR", Alias: "REDIS_ADDR", Source: "legacy"}}
+	if !reflect.DeepEqual(deprecations, expected) {
+		t.Errorf("expected a single warning %v, got %v", expected, deprecations)
+	}
+}
+
+func TestEnv_Prefix(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"BILLING_PORT": "8080", "BILLING_DB_URL": "postgres://billing", "PORT": "80"}))
+	billing := env.Prefix("BILLING_")
+	if value := lazyenv.MustGetFrom(billing, "PORT", lazyenv.Int); value != 8080 {
+		t.Errorf("expected 8080, got %d", value)
+	}
+	if value := lazyenv.MustGetFrom[string](billing.Prefix("DB_"), "URL"); value != "postgres://billing" {
+		t.Errorf("expected postgres://billing, got %s", value)
+	}
+	if value := lazyenv.MustGetFrom(env, "PORT", lazyenv.Int); value != 80 {
+		t.Errorf("expected the Env to be unaffected by its views, got %d", value)
+	}
+	if value, _ := billing.Lookup("DB_URL"); value != "postgres://billing" {
+		t.Errorf("expected Lookup to qualify the key, got %s", value)
+	}
+}
+
+func TestEnv_Prefix_Option(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"BILLING_DB_URL": "postgres://billing"}), lazyenv.WithPrefix("BILLING_"))
+	if value := lazyenv.MustGetFrom[string](env.Prefix("DB_"), "URL"); value != "postgres://billing" {
+		t.Errorf("expected postgres://billing, got %s", value)
+	}
+}
+
+func TestEnv_Prefix_Errors(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"BILLING_DB_PORT": "abc"})).Prefix("BILLING_").Prefix("DB_")
+	var key string
+	lazyenv.GetFrom(env, "HOST", func(params lazyenv.GetDefaultValueParams) (string, error) {
+		key = params.Key
+		return "", nil
+	})
+	if key != "BILLING_DB_HOST" {
+		t.Errorf("expected the default value getter to get BILLING_DB_HOST, got %s", key)
+	}
+	_, err := lazyenv.GetFrom(env, "HOST", lazyenv.Required[string])
+	if err == nil || err.Error() != "required variable not found: BILLING_DB_HOST" {
+		t.Errorf("expected the error to report BILLING_DB_HOST, got %v", err)
+	}
+	_, err = lazyenv.GetFrom(env, "PORT", lazyenv.Required[int], lazyenv.Strict(lazyenv.Int))
+	var parseErr *lazyenv.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Key != "BILLING_DB_PORT" {
+		t.Errorf("expected a *ParseError for BILLING_DB_PORT, got %v", err)
+	}
+}
+
+func TestDefault_Concurrent(t *testing.T) {
+	lazyenv.SetSource(lazyenv.Map{"TEST_DEFAULT_CONCURRENT": "42"})
+	defer lazyenv.SetSource(lazyenv.OS)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if value := lazyenv.MustGet("TEST_DEFAULT_CONCURRENT", lazyenv.Int); value != 42 {
+					t.Errorf("expected 42, got %d", value)
+				}
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				switch (i + j) % 3 {
+				case 0:
+					lazyenv.Reset()
+				case 1:
+					lazyenv.SetStrict(false)
+				case 2:
+					lazyenv.SetSource(lazyenv.Map{"TEST_DEFAULT_CONCURRENT": "42"})
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func BenchmarkGet_Parallel(b *testing.B) {
+	lazyenv.SetSource(lazyenv.Map{"PORT": "8080"})
+	defer lazyenv.SetSource(lazyenv.OS)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			lazyenv.MustGet("PORT", lazyenv.Int)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package lazyenv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrNotFound is the cause of a *VarError for a required variable that is not set
+	ErrNotFound = errors.New("required variable not found")
+	// ErrParse is matched by every *ParseError
+	ErrParse = errors.New("failed to parse variable")
+	// ErrCast is the cause of a *VarError for a value that is not of the requested type, usually because the mapper is missing
+	ErrCast = errors.New("failed to cast variable")
+	// ErrCycle is the cause of a *VarError for variables that reference each other when expansion is enabled
+	ErrCycle = errors.New("reference cycle")
+)
+
+// VarError is returned when a variable cannot be read. Cause is ErrNotFound, ErrCast, a *ParseError or the error that caused the failure,
+// Source is the name of the source that supplied Value, if it was set. Keys lists every key that was tried when Key has aliases
+type VarError struct {
+	Key    string
+	Keys   []string
+	Value  string
+	Source string
+	Cause  error
+}
+
+func (e *VarError) Error() string {
+	var parseErr *ParseError
+	switch {
+	// Required reports values that cannot be parsed with the message it has always used, the *ParseError is available with errors.As
+	case e.Cause == ErrNotFound, errors.As(e.Cause, &parseErr):
+		if len(e.Keys) > 1 {
+			return fmt.Sprintf("required variable not found: %s (also tried %s)", e.Key, strings.Join(e.Keys[1:], ", "))
+		}
+		return "required variable not found: " + e.Key
+	case e.Cause == ErrCast:
+		return fmt.Sprintf("failed to cast %v, did you forget to add a mapper?", e.Value)
+	}
+	return fmt.Sprintf("%s: %v", e.Key, e.Cause)
+}
+
+func (e *VarError) Unwrap() error {
+	return e.Cause
+}
+
+// ParseError is returned in strict mode when the mapper fails to parse a value that is set, it matches ErrParse.
+// Key is the key that supplied Value, which is an alias when the key itself is not set. Keys lists every key that was tried when it has aliases
+type ParseError struct {
+	Key    string
+	Keys   []string
+	Value  string
+	Source string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if len(e.Keys) > 1 {
+		return fmt.Sprintf("failed to parse %s=%q (tried %s): %v", e.Key, e.Value, strings.Join(e.Keys, ", "), e.Err)
+	}
+	return fmt.Sprintf("failed to parse %s=%q: %v", e.Key, e.Value, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ParseError) Is(target error) bool {
+	return target == ErrParse
+}
+
+type strictError struct {
+	err error
+}
+
+func (e *strictError) Error() string {
+	return e.err.Error()
+}
+
+func (e *strictError) Unwrap() error {
+	return e.err
+}
+
+// Errors is a list of errors reported together. errors.Is and errors.As match it if they match any of the errors in the list
+type Errors []error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+func (e Errors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e Errors) As(target any) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Collector records the errors of many calls to Collect instead of stopping at the first one,
+// so that every missing or invalid variable can be reported at once
+type Collector struct {
+	env    *Env
+	mu     sync.Mutex
+	errors Errors
+}
+
+// NewCollector creates a Collector that reads values from the given Env
+func NewCollector(env *Env) *Collector {
+	return &Collector{env: env}
+}
+
+// Collect works like GetFrom, but instead of returning the error it records it in the Collector
+func Collect[T any](c *Collector, key string, getDefaultValue GetDefaultValue[T], optionalMapper ...Mapper[T]) T {
+	value, err := GetFrom(c.env, key, getDefaultValue, optionalMapper...)
+	c.Add(err)
+	return value
+}
+
+// Add records an error that is not the result of Collect, nil errors are ignored
+func (c *Collector) Add(err error) {
+	if err == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if list, ok := err.(Errors); ok {
+		c.errors = append(c.errors, list...)
+		return
+	}
+	c.errors = append(c.errors, err)
+}
+
+// Err returns every recorded error as Errors, or nil if there were none
+func (c *Collector) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.errors) == 0 {
+		return nil
+	}
+	return append(Errors(nil), c.errors...)
+}
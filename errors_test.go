@@ -0,0 +1,161 @@
+package lazyenv_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+var errCollectorTest = errors.New("collector test")
+
+func TestCollector(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"PORT": "8080", "NAME": "app"}))
+	c := lazyenv.NewCollector(env)
+	port := lazyenv.Collect(c, "PORT", lazyenv.Required[int], lazyenv.Int)
+	name := lazyenv.Collect(c, "NAME", lazyenv.Required[string])
+	if err := c.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if port != 8080 || name != "app" {
+		t.Errorf("expected 8080 and app, got %d and %s", port, name)
+	}
+}
+
+func TestCollector_Errors(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{}))
+	c := lazyenv.NewCollector(env)
+	lazyenv.Collect(c, "FIRST", lazyenv.Required[string])
+	lazyenv.Collect(c, "SECOND", lazyenv.Required[int], lazyenv.Int)
+	lazyenv.Collect(c, "OPTIONAL", lazyenv.Optional[string])
+	c.Add(nil)
+	c.Add(errCollectorTest)
+	err := c.Err()
+	var list lazyenv.Errors
+	if !errors.As(err, &list) || len(list) != 3 {
+		t.Fatalf("expected 3 errors, got %v", err)
+	}
+	expected := "required variable not found: FIRST\nrequired variable not found: SECOND\ncollector test"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+	if !errors.Is(err, errCollectorTest) {
+		t.Error("expected errors.Is to find the added error")
+	}
+}
+
+func TestBind_AllErrors(t *testing.T) {
+	t.Parallel()
+	var config struct {
+		Host string `env:"HOST" required:"true"`
+		Port int    `env:"PORT" required:"true"`
+		Name string `env:"NAME" default:"app"`
+	}
+	err := lazyenv.BindFrom(lazyenv.New(lazyenv.WithSource(lazyenv.Map{})), &config)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "HOST") || !strings.Contains(err.Error(), "PORT") {
+		t.Errorf("expected both HOST and PORT to be reported, got %s", err)
+	}
+	if config.Name != "app" {
+		t.Errorf("expected fields after a failure to be bound, got %s", config.Name)
+	}
+}
+
+func TestErrors_NotFound(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{}))
+	_, err := lazyenv.GetFrom(env, "MISSING", lazyenv.Required[string])
+	if !errors.Is(err, lazyenv.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	var varErr *lazyenv.VarError
+	if !errors.As(err, &varErr) || varErr.Key != "MISSING" {
+		t.Errorf("expected VarError for MISSING, got %v", err)
+	}
+}
+
+func TestErrors_Cast(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Named("test", lazyenv.Map{"PORT": "8080"})))
+	_, err := lazyenv.GetFrom(env, "PORT", lazyenv.Required[int])
+	if !errors.Is(err, lazyenv.ErrCast) {
+		t.Errorf("expected ErrCast, got %v", err)
+	}
+	var varErr *lazyenv.VarError
+	if !errors.As(err, &varErr) || varErr.Key != "PORT" || varErr.Value != "8080" || varErr.Source != "test" {
+		t.Errorf("unexpected VarError: %+v", varErr)
+	}
+}
+
+func TestErrors_Parse(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithStrict(), lazyenv.WithSource(lazyenv.Named("test", lazyenv.Map{"PORT": "80a"})))
+	_, err := lazyenv.GetFrom(env, "PORT", lazyenv.Required[int], lazyenv.Int)
+	if !errors.Is(err, lazyenv.ErrParse) {
+		t.Errorf("expected ErrParse, got %v", err)
+	}
+	if errors.Is(err, lazyenv.ErrNotFound) {
+		t.Error("expected parse error not to match ErrNotFound")
+	}
+	var parseErr *lazyenv.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Source != "test" {
+		t.Errorf("unexpected ParseError: %+v", parseErr)
+	}
+}
+
+func TestErrors_MustGet(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !errors.Is(err, lazyenv.ErrNotFound) {
+			t.Errorf("expected MustGetFrom to panic with ErrNotFound, got %v", err)
+		}
+	}()
+	lazyenv.MustGetFrom[string](lazyenv.New(lazyenv.WithSource(lazyenv.Map{})), "MISSING")
+}
+
+func TestErrors_Collector(t *testing.T) {
+	t.Parallel()
+	c := lazyenv.NewCollector(lazyenv.New(lazyenv.WithSource(lazyenv.Map{})))
+	lazyenv.Collect(c, "MISSING", lazyenv.Required[string])
+	var varErr *lazyenv.VarError
+	if err := c.Err(); !errors.Is(err, lazyenv.ErrNotFound) || !errors.As(err, &varErr) {
+		t.Errorf("expected collected errors to match ErrNotFound and VarError, got %v", err)
+	}
+}
+
+func TestErrors_MustGet_Parse(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		err, ok := recover().(error)
+		var parseErr *lazyenv.ParseError
+		if !ok || !errors.As(err, &parseErr) || errors.Is(err, lazyenv.ErrNotFound) {
+			t.Fatalf("expected MustGetFrom to panic with a *ParseError, got %v", err)
+		}
+		if parseErr.Key != "PORT" || parseErr.Value != "80a" || parseErr.Source != "test" || parseErr.Err == nil {
+			t.Errorf("unexpected ParseError: %+v", parseErr)
+		}
+		if expected := `failed to parse PORT="80a": strconv.Atoi: parsing "80a": invalid syntax`; err.Error() != expected {
+			t.Errorf("expected %q, got %q", expected, err.Error())
+		}
+	}()
+	lazyenv.MustGetFrom(lazyenv.New(lazyenv.WithSource(lazyenv.Named("test", lazyenv.Map{"PORT": "80a"}))), "PORT", lazyenv.Int)
+}
+
+func TestErrors_Required_Parse(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"PORT": "80a"}))
+	_, err := lazyenv.GetFrom(env, "PORT", lazyenv.Required[int], lazyenv.Int)
+	if !errors.Is(err, lazyenv.ErrParse) || errors.Is(err, lazyenv.ErrNotFound) {
+		t.Errorf("expected a value that cannot be parsed to match ErrParse and not ErrNotFound, got %v", err)
+	}
+	var parseErr *lazyenv.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Key != "PORT" || parseErr.Value != "80a" {
+		t.Errorf("unexpected ParseError: %+v", parseErr)
+	}
+}
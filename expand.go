@@ -0,0 +1,111 @@
+package lazyenv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// expand replaces references to other variables in value, using the shell syntax:
+// ${KEY} is replaced by the value of KEY or an empty string if it is not set,
+// ${KEY:-fallback} and ${KEY-fallback} use fallback if KEY is unset or empty, or only if it is unset,
+// ${KEY:?message} and ${KEY?message} fail with message if KEY is unset or empty, or only if it is unset.
+// $$ and \$ produce a literal $, a $ that is not followed by { is kept as it is
+func expand(value string, lookup func(key string) (string, bool, error)) (string, error) {
+	if !strings.Contains(value, "$") {
+		return value, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		next := byte(0)
+		if i+1 < len(value) {
+			next = value[i+1]
+		}
+		switch {
+		case (c == '\\' || c == '$') && next == '$':
+			b.WriteByte('$')
+			i++
+		case c == '$' && next == '{':
+			end := matchingBrace(value, i+2)
+			if end < 0 {
+				return "", errors.New("unterminated ${ in value")
+			}
+			result, err := expandReference(value[i+2:end], lookup)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(result)
+			i = end
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+// matchingBrace returns the index of the } closing the reference that starts at start, or -1
+func matchingBrace(value string, start int) int {
+	depth := 1
+	for i := start; i < len(value); i++ {
+		switch value[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func expandReference(reference string, lookup func(key string) (string, bool, error)) (string, error) {
+	n := 0
+	for n < len(reference) && isReferenceName(reference[n]) {
+		n++
+	}
+	key, operator := reference[:n], reference[n:]
+	if key == "" {
+		return "", fmt.Errorf("invalid reference ${%s}", reference)
+	}
+	value, exists, err := lookup(key)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case operator == "":
+		return value, nil
+	case strings.HasPrefix(operator, ":-"):
+		if !exists || value == "" {
+			return expand(operator[2:], lookup)
+		}
+	case strings.HasPrefix(operator, "-"):
+		if !exists {
+			return expand(operator[1:], lookup)
+		}
+	case strings.HasPrefix(operator, ":?"):
+		if !exists || value == "" {
+			return "", referenceError(key, operator[2:])
+		}
+	case strings.HasPrefix(operator, "?"):
+		if !exists {
+			return "", referenceError(key, operator[1:])
+		}
+	default:
+		return "", fmt.Errorf("invalid reference ${%s}", reference)
+	}
+	return value, nil
+}
+
+func referenceError(key string, message string) error {
+	if message == "" {
+		return &VarError{Key: key, Cause: ErrNotFound}
+	}
+	return &VarError{Key: key, Cause: fmt.Errorf("%w: %s", ErrNotFound, message)}
+}
+
+func isReferenceName(c byte) bool {
+	return c == '_' || c == '.' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
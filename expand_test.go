@@ -0,0 +1,133 @@
+package lazyenv_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+func TestExpansion(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithExpansion(), lazyenv.WithSource(lazyenv.Map{
+		"DB_USER":      "admin",
+		"DB_HOST":      "localhost",
+		"DATABASE_URL": "postgres://${DB_USER}@${DB_HOST}/app",
+	}))
+	if value := lazyenv.MustGetFrom[string](env, "DATABASE_URL"); value != "postgres://admin@localhost/app" {
+		t.Errorf("expected postgres://admin@localhost/app, got %s", value)
+	}
+}
+
+func TestExpansion_Disabled(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"HOST": "localhost", "URL": "http://${HOST}"}))
+	if value := lazyenv.MustGetFrom[string](env, "URL"); value != "http://${HOST}" {
+		t.Errorf("expected the literal value, got %s", value)
+	}
+}
+
+func TestExpansion_Syntax(t *testing.T) {
+	t.Parallel()
+	source := lazyenv.Map{"SET": "value", "EMPTY": ""}
+	tests := map[string]string{
+		"${SET}":                    "value",
+		"${UNSET}":                  "",
+		"${UNSET:-fallback}":        "fallback",
+		"${EMPTY:-fallback}":        "fallback",
+		"${EMPTY-fallback}":         "",
+		"${UNSET-fallback}":         "fallback",
+		"${SET:-fallback}":          "value",
+		"${UNSET:-${SET}}":          "value",
+		"${UNSET:-{}}":              "{}",
+		"${EMPTY?missing}":          "",
+		"$$SET":                     "$SET",
+		`\${SET}`:                   "${SET}",
+		"price: 5$ or $5":           "price: 5$ or $5",
+		"${SET}-${SET}":             "value-value",
+		"${lower.case_1:-fallback}": "fallback",
+	}
+	for input, expected := range tests {
+		source["VALUE"] = input
+		env := lazyenv.New(lazyenv.WithExpansion(), lazyenv.WithSource(source))
+		value, err := lazyenv.GetFrom[string](env, "VALUE", lazyenv.Required[string])
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", input, err)
+			continue
+		}
+		if value != expected {
+			t.Errorf("%s: expected %q, got %q", input, expected, value)
+		}
+	}
+}
+
+func TestExpansion_Required(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithExpansion(), lazyenv.WithSource(lazyenv.Map{
+		"URL":   "http://${HOST:?set HOST to the server name}",
+		"EMPTY": "",
+		"OTHER": "${EMPTY:?}",
+	}))
+	_, err := lazyenv.GetFrom[string](env, "URL", lazyenv.Required[string])
+	if !errors.Is(err, lazyenv.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if expected := "URL: HOST: required variable not found: set HOST to the server name"; err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+	var varErr *lazyenv.VarError
+	if !errors.As(err, &varErr) || varErr.Key != "URL" {
+		t.Errorf("expected a *VarError for URL, got %v", err)
+	}
+	if inner, ok := varErr.Cause.(*lazyenv.VarError); !ok || inner.Key != "HOST" {
+		t.Errorf("expected the cause to be a *VarError for HOST, got %v", varErr.Cause)
+	}
+	_, err = lazyenv.GetFrom[string](env, "OTHER", lazyenv.Required[string])
+	if err == nil || err.Error() != "OTHER: required variable not found: EMPTY" {
+		t.Errorf("expected the EMPTY reference to be required, got %v", err)
+	}
+}
+
+func TestExpansion_Cycle(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithExpansion(), lazyenv.WithSource(lazyenv.Map{
+		"A":    "${B}",
+		"B":    "x${C}",
+		"C":    "${A}",
+		"SELF": "${SELF:-fallback}",
+	}))
+	_, err := lazyenv.GetFrom[string](env, "A", lazyenv.Required[string])
+	if !errors.Is(err, lazyenv.ErrCycle) {
+		t.Fatalf("expected ErrCycle, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "A -> B -> C -> A") {
+		t.Errorf("expected the error to show the cycle, got %s", err)
+	}
+	if _, err := lazyenv.GetFrom[string](env, "SELF", lazyenv.Required[string]); !errors.Is(err, lazyenv.ErrCycle) {
+		t.Errorf("expected a reference to itself to be a cycle, got %v", err)
+	}
+}
+
+func TestExpansion_Invalid(t *testing.T) {
+	t.Parallel()
+	for _, value := range []string{"${UNTERMINATED", "${}", "${KEY:x}"} {
+		env := lazyenv.New(lazyenv.WithExpansion(), lazyenv.WithSource(lazyenv.Map{"VALUE": value}))
+		_, err := lazyenv.GetFrom[string](env, "VALUE", lazyenv.Required[string])
+		var varErr *lazyenv.VarError
+		if !errors.As(err, &varErr) || varErr.Key != "VALUE" {
+			t.Errorf("%s: expected a *VarError for VALUE, got %v", value, err)
+		}
+	}
+}
+
+func TestExpansion_Cache(t *testing.T) {
+	t.Parallel()
+	source := lazyenv.Map{"HOST": "first", "URL": "http://${HOST}"}
+	env := lazyenv.New(lazyenv.WithExpansion(), lazyenv.WithSource(source))
+	lazyenv.MustGetFrom[string](env, "URL")
+	source["HOST"] = "second"
+	if value := lazyenv.MustGetFrom[string](env, "HOST"); value != "first" {
+		t.Errorf("expected the referenced value to be cached, got %s", value)
+	}
+}
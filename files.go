@@ -0,0 +1,89 @@
+package lazyenv
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Dir is a source that reads each key from the file of the same name in a directory, which is how Kubernetes mounts ConfigMaps and secrets.
+// Files are read when they are looked up, so updates Kubernetes makes by swapping the ..data symlink are picked up after Reset.
+// A single trailing newline is removed from the content of each file
+type Dir string
+
+func (d Dir) Lookup(key string) (string, bool) {
+	value, exists, _ := d.TryLookup(key)
+	return value, exists
+}
+
+func (d Dir) TryLookup(key string) (string, bool, error) {
+	if !isDirKey(key) {
+		return "", false, nil
+	}
+	path := filepath.Join(string(d), key)
+	if info, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) || (err == nil && info.IsDir()) {
+		return "", false, nil
+	}
+	value, err := readValueFile(path)
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+// Keys lists the regular files in the directory, following symlinks and skipping hidden entries such as ..data
+func (d Dir) Keys() []string {
+	entries, err := os.ReadDir(string(d))
+	if err != nil {
+		return nil
+	}
+	var keys []string
+	for _, entry := range entries {
+		if !isDirKey(entry.Name()) {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(string(d), entry.Name()))
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		keys = append(keys, entry.Name())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (d Dir) String() string {
+	return string(d)
+}
+
+// isDirKey reports whether key names a file directly inside the directory, rather than a hidden entry or a path elsewhere
+func isDirKey(key string) bool {
+	return key != "" && !strings.HasPrefix(key, ".") && !strings.ContainsAny(key, `/\`)
+}
+
+// readValueFile reads a value stored in a file, such as a mounted secret, without its trailing newline
+func readValueFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a regular file", path)
+	}
+	if info.Mode().Perm()&0o444 == 0 {
+		return "", fmt.Errorf("%s is not readable, its permissions are %s: %w", path, info.Mode().Perm(), os.ErrPermission)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	value := string(content)
+	if strings.HasSuffix(value, "\r\n") {
+		return value[:len(value)-2], nil
+	}
+	return strings.TrimSuffix(value, "\n"), nil
+}
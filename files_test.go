@@ -0,0 +1,141 @@
+package lazyenv_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+func TestFileSuffix(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "db")
+	os.WriteFile(path, []byte("hunter2\n"), 0o400)
+	env := lazyenv.New(lazyenv.WithFileSuffix("_FILE"), lazyenv.WithSource(lazyenv.Map{
+		"DB_PASSWORD_FILE": path,
+		"DB_USER":          "direct",
+		"DB_USER_FILE":     path,
+	}))
+	value, err := lazyenv.GetFrom(env, "DB_PASSWORD", lazyenv.Required[string])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "hunter2" {
+		t.Errorf("expected hunter2, got %q", value)
+	}
+	if origin, _ := env.Origin("DB_PASSWORD"); origin != path {
+		t.Errorf("expected %s, got %s", path, origin)
+	}
+	if value := lazyenv.MustGetFrom[string](env, "DB_USER"); value != "direct" {
+		t.Errorf("expected the variable to win over the file, got %s", value)
+	}
+}
+
+func TestFileSuffix_Mapper(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "port")
+	os.WriteFile(path, []byte("8080\r\n"), 0o600)
+	env := lazyenv.New(lazyenv.WithFileSuffix("_FILE"), lazyenv.WithSource(lazyenv.Map{"PORT_FILE": path}))
+	if value := lazyenv.MustGetFrom(env, "PORT", lazyenv.Int); value != 8080 {
+		t.Errorf("expected 8080, got %d", value)
+	}
+}
+
+func TestFileSuffix_Disabled(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"DB_PASSWORD_FILE": "/does/not/matter"}))
+	_, err := lazyenv.GetFrom(env, "DB_PASSWORD", lazyenv.Required[string])
+	if !errors.Is(err, lazyenv.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFileSuffix_Missing(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "missing")
+	env := lazyenv.New(lazyenv.WithFileSuffix("_FILE"), lazyenv.WithSource(lazyenv.Map{"DB_PASSWORD_FILE": path}))
+	_, err := lazyenv.GetFrom(env, "DB_PASSWORD", lazyenv.OrReturn("default"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+	var varErr *lazyenv.VarError
+	if !errors.As(err, &varErr) || varErr.Key != "DB_PASSWORD" || varErr.Value != path {
+		t.Errorf("unexpected VarError: %+v", varErr)
+	}
+}
+
+func TestFileSuffix_Unreadable(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "db")
+	os.WriteFile(path, []byte("hunter2"), 0o200)
+	env := lazyenv.New(lazyenv.WithFileSuffix("_FILE"), lazyenv.WithSource(lazyenv.Map{"DB_PASSWORD_FILE": path, "DIR_FILE": dir}))
+	if _, err := lazyenv.GetFrom(env, "DB_PASSWORD", lazyenv.Required[string]); !errors.Is(err, os.ErrPermission) {
+		t.Errorf("expected permission error, got %v", err)
+	}
+	if _, err := lazyenv.GetFrom(env, "DIR", lazyenv.Required[string]); err == nil || errors.Is(err, lazyenv.ErrNotFound) {
+		t.Errorf("expected an error for a directory, got %v", err)
+	}
+}
+
+func TestDir(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "DB_HOST"), []byte("localhost\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "DB_PORT"), []byte("5432"), 0o644)
+	os.WriteFile(filepath.Join(dir, ".hidden"), []byte("hidden"), 0o644)
+	os.Mkdir(filepath.Join(dir, "nested"), 0o755)
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Dir(dir)))
+	if value := lazyenv.MustGetFrom[string](env, "DB_HOST"); value != "localhost" {
+		t.Errorf("expected localhost, got %q", value)
+	}
+	if value := lazyenv.MustGetFrom(env, "DB_PORT", lazyenv.Int); value != 5432 {
+		t.Errorf("expected 5432, got %d", value)
+	}
+	if origin, _ := env.Origin("DB_HOST"); origin != dir {
+		t.Errorf("expected %s, got %s", dir, origin)
+	}
+	for _, key := range []string{"MISSING", ".hidden", "nested", "../DB_HOST", ""} {
+		if _, err := lazyenv.GetFrom(env, key, lazyenv.Required[string]); !errors.Is(err, lazyenv.ErrNotFound) {
+			t.Errorf("expected %q to be missing, got %v", key, err)
+		}
+	}
+	keys := lazyenv.Dir(dir).Keys()
+	if len(keys) != 2 || keys[0] != "DB_HOST" || keys[1] != "DB_PORT" {
+		t.Errorf("expected [DB_HOST DB_PORT], got %v", keys)
+	}
+}
+
+func TestDir_Kubernetes(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	os.Mkdir(filepath.Join(dir, "..2024_01_01_00_00_00.000000000"), 0o755)
+	os.WriteFile(filepath.Join(dir, "..2024_01_01_00_00_00.000000000", "DB_HOST"), []byte("db.internal"), 0o644)
+	os.Symlink("..2024_01_01_00_00_00.000000000", filepath.Join(dir, "..data"))
+	os.Symlink(filepath.Join("..data", "DB_HOST"), filepath.Join(dir, "DB_HOST"))
+	source := lazyenv.Dir(dir)
+	if value, ok := source.Lookup("DB_HOST"); !ok || value != "db.internal" {
+		t.Errorf("expected db.internal, got %q", value)
+	}
+	keys := source.Keys()
+	if len(keys) != 1 || keys[0] != "DB_HOST" {
+		t.Errorf("expected [DB_HOST], got %v", keys)
+	}
+}
+
+func TestDir_Unreadable(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "DB_PASSWORD"), []byte("hunter2"), 0o200)
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Chain{lazyenv.Map{}, lazyenv.Dir(dir)}))
+	_, err := lazyenv.GetFrom(env, "DB_PASSWORD", lazyenv.OrReturn("default"))
+	if !errors.Is(err, os.ErrPermission) {
+		t.Errorf("expected permission error, got %v", err)
+	}
+	var varErr *lazyenv.VarError
+	if !errors.As(err, &varErr) || varErr.Source != dir {
+		t.Errorf("expected the error to name %s, got %+v", dir, varErr)
+	}
+}
@@ -3,68 +3,80 @@ package lazyenv
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
-	"os"
 	"strconv"
 	"strings"
-	"sync"
 )
 
-type cache struct {
-	sync.Mutex
-	values map[string]string
+// Reason tells a default value getter why it was called
+type Reason int
+
+const (
+	// Missing means the variable is not set
+	Missing Reason = iota
+	// ParseFailed means the variable is set, but the mapper could not parse it
+	ParseFailed
+)
+
+func (r Reason) String() string {
+	if r == ParseFailed {
+		return "parse failed"
+	}
+	return "missing"
 }
 
+// GetDefaultValueParams is passed to default value getters. RawValue, MapperError and Source are only set when Reason is ParseFailed.
+// Keys lists every key that was tried, Key first and then its aliases, see WithAliases
 type GetDefaultValueParams struct {
-	Key string
+	Key         string
+	Keys        []string
+	Reason      Reason
+	RawValue    string
+	MapperError error
+	Source      string
 }
 
 type GetDefaultValue[T any] func(params GetDefaultValueParams) (T, error)
 type Mapper[T any] func(value string) (T, error)
 
-var cacheInstance *cache
-
-func castAs[T any](v any) (T, error) {
-	cast, ok := v.(T)
+func castAs[T any](key string, value entry) (T, error) {
+	cast, ok := any(value.value).(T)
 	if !ok {
-		return cast, fmt.Errorf("failed to cast %v, did you forget to add a mapper?", v)
+		if isSecret(cast) {
+			value.value = redacted
+		}
+		return cast, &VarError{Key: key, Value: value.value, Source: value.origin, Cause: ErrCast}
 	}
 	return cast, nil
 }
 
-func (c *cache) get(key string) (string, bool) {
-	c.Lock()
-	defer c.Unlock()
-	value, exists := c.values[key]
-	return value, exists
+// Reset clears the cache of the default Env so that each call to Get will fetch the value from the environment
+func Reset() {
+	Default().Reset()
 }
 
-func (c *cache) set(key string, value string) {
-	c.Lock()
-	defer c.Unlock()
-	c.values[key] = value
+// SetSource replaces the default Env with one that reads from the given source, with an empty cache and the same options
+func SetSource(source Source) {
+	updateDefault(func(env *Env) {
+		env.source = source
+		env.cache = newCache()
+	})
 }
 
-// Reset clears the cache so that each call to Get will fetch the value from the environment
-func Reset () {
-	cacheInstance.Lock()
-	defer cacheInstance.Unlock()
-	cacheInstance.values = make(map[string]string)
+// SetStrict turns strict mode of the default Env on or off, see WithStrict
+func SetStrict(strict bool) {
+	updateDefault(func(env *Env) {
+		env.strict = strict
+	})
 }
 
-func getEnv(key string) (string, bool) {
-	if cacheInstance == nil {
-		cacheInstance = &cache{values: make(map[string]string)}
-	}
-	value, exists := cacheInstance.get(key)
-	if !exists {
-		value, exists = os.LookupEnv(key)
-		if !exists {
-			return "", false
-		}
-		cacheInstance.set(key, value)
-	}
-	return value, true
+// Prefix returns a view of the current default Env that qualifies every key with prefix, see Env.Prefix
+func Prefix(prefix string) *Env {
+	return Default().Prefix(prefix)
+}
+
+// Origin returns the name of the source the default Env read the given key from
+func Origin(key string) (string, bool) {
+	return Default().Origin(key)
 }
 
 // Get returns the value of the environment variable with the given key
@@ -73,35 +85,93 @@ func getEnv(key string) (string, bool) {
 // if the first element of the variadic parameter is provided, it will be considered, otherwise the value
 // will be returned as a string
 func Get[T any](key string, getDefaultValue GetDefaultValue[T], optionalMapper ...Mapper[T]) (T, error) {
-	value, exists := getEnv(key)
+	return GetFrom(Default(), key, getDefaultValue, optionalMapper...)
+}
+
+// GetFrom works like Get, but reads the value from the given Env instead of the default one
+// when the mapper fails, the value is treated as missing, unless the Env or the mapper is strict, in which case a *ParseError is returned.
+// The parsed value is cached if the Env was created WithParsedCache
+func GetFrom[T any](env *Env, key string, getDefaultValue GetDefaultValue[T], optionalMapper ...Mapper[T]) (T, error) {
+	key = env.prefix + key
+	value, keys, exists, err := env.resolveAliases(key, nil)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
 	if !exists {
 		return getDefaultValue(GetDefaultValueParams{
-			key,
+			Key:    key,
+			Keys:   keys,
+			Reason: Missing,
 		})
 	}
 	if len(optionalMapper) > 0 {
-		val, err := optionalMapper[0](value)
+		if env.parsed {
+			if val, exists := getParsed(env.cache, keys[len(keys)-1], value.value, optionalMapper[0]); exists {
+				return val, nil
+			}
+		}
+		val, err := optionalMapper[0](value.value)
 		if err != nil {
+			var zero T
+			var secret *secretError
+			if errors.As(err, &secret) {
+				value.value = redacted
+			}
+			var strict *strictError
+			if env.strict || errors.As(err, &strict) {
+				return zero, &ParseError{Key: keys[len(keys)-1], Keys: keys, Value: value.value, Source: value.origin, Err: err}
+			}
 			return getDefaultValue(GetDefaultValueParams{
-				key,
+				Key:         key,
+				Keys:        keys,
+				Reason:      ParseFailed,
+				RawValue:    value.value,
+				MapperError: err,
+				Source:      value.origin,
 			})
 		}
+		if env.parsed {
+			setParsed(env.cache, keys[len(keys)-1], value.value, optionalMapper[0], val)
+		}
 		return val, nil
 	}
-	return castAs[T](value)
+	return castAs[T](keys[len(keys)-1], value)
 }
 
-// MustGet returns the value or panics if it's not available
+// MustGet returns the value or panics, with a *VarError matching ErrNotFound if it's not available or a *ParseError if it cannot be parsed
 func MustGet[T any](key string, optionalMapper ...Mapper[T]) T {
-	// it's ok to ignore err, OrPanic stops Get from returning it
-	value, _ := Get(key, OrPanic[T], optionalMapper...)
+	return MustGetFrom(Default(), key, optionalMapper...)
+}
+
+// MustGetFrom works like MustGet, but reads the value from the given Env instead of the default one
+func MustGetFrom[T any](env *Env, key string, optionalMapper ...Mapper[T]) T {
+	value, err := GetFrom(env, key, OrPanic[T], optionalMapper...)
+	if err != nil {
+		panic(err)
+	}
 	return value
 }
 
-// Required is a default value getter that returns the value of the environment variable if it is set, otherwise it returns an error
+// Strict wraps a mapper so that Get returns a *ParseError when it fails, instead of calling getDefaultValue
+func Strict[T any](mapper Mapper[T]) Mapper[T] {
+	return func(value string) (T, error) {
+		result, err := mapper(value)
+		if err != nil {
+			return result, &strictError{err}
+		}
+		return result, nil
+	}
+}
+
+// Required is a default value getter that returns the value of the environment variable if it is set, otherwise it returns an error.
+// When the value cannot be parsed, the error still reads "required variable not found", but it wraps a *ParseError and matches ErrParse instead of ErrNotFound
 func Required[T any](params GetDefaultValueParams) (T, error) {
 	var v T
-	return v, errors.New("required variable not found: " + params.Key)
+	if params.Reason == ParseFailed {
+		return v, &VarError{Key: params.Key, Keys: params.Keys, Value: params.RawValue, Source: params.Source, Cause: parseErrorOf(params)}
+	}
+	return v, &VarError{Key: params.Key, Keys: params.Keys, Value: params.RawValue, Source: params.Source, Cause: ErrNotFound}
 }
 
 // Optional is a default value getter that returns the value of the environment variable if it is set, otherwise it returns the value the type initialises to and no error
@@ -117,8 +187,20 @@ func OrReturn[T any](defaultValue T) func (key GetDefaultValueParams) (T, error)
 	}
 }
 
+// OrPanic is a default value getter that panics with a *VarError if the variable is not set, or with a *ParseError if it cannot be parsed
 func OrPanic[T any](params GetDefaultValueParams) (T, error) {
-	panic(errors.New("required variable not found: " + params.Key))
+	if params.Reason == ParseFailed {
+		panic(parseErrorOf(params))
+	}
+	panic(&VarError{Key: params.Key, Keys: params.Keys, Value: params.RawValue, Source: params.Source, Cause: ErrNotFound})
+}
+
+func parseErrorOf(params GetDefaultValueParams) *ParseError {
+	key := params.Key
+	if len(params.Keys) > 0 {
+		key = params.Keys[len(params.Keys)-1]
+	}
+	return &ParseError{Key: key, Keys: params.Keys, Value: params.RawValue, Source: params.Source, Err: params.MapperError}
 }
 
 // String is a mapper that returns the value of the variable as a string
@@ -205,6 +287,15 @@ func Uint64(value string) (uint64, error) {
 	return strconv.ParseUint(value, 10, 64)
 }
 
+// Uintptr is a mapper that returns the value of the variable as a uintptr
+func Uintptr(value string) (uintptr, error) {
+	u64, err := strconv.ParseUint(value, 10, strconv.IntSize)
+	if err != nil {
+		return 0, err
+	}
+	return uintptr(u64), nil
+}
+
 // Float32 is a mapper that returns the value of the variable as a float32
 func Float32(value string) (float32, error) {
 	f64, err := strconv.ParseFloat(value, 32)
@@ -303,7 +303,7 @@ func TestLazyGet_Float32_Invalid(t *testing.T) {
 func TestLazyGet_Uintptr(t *testing.T) {
 	os.Setenv("TEST_UINT", "1")
 	defer os.Unsetenv("TEST_UINT")
-	value, err := lazyenv.Get("TEST_UINT", lazyenv.Required[uint], lazyenv.Uint)
+	value, err := lazyenv.Get("TEST_UINT", lazyenv.Required[uintptr], lazyenv.Uintptr)
 	if err != nil {
 		t.Error(err)
 	}
@@ -315,7 +315,7 @@ func TestLazyGet_Uintptr(t *testing.T) {
 func TestLazyGet_Uintptr_Invalid(t *testing.T) {
 	os.Setenv("TEST_UINT_INVALID", "zzz")
 	defer os.Unsetenv("TEST_UINT_INVALID")
-	value, err := lazyenv.Get("TEST_UINT_INVALID", lazyenv.Required[uint], lazyenv.Uint)
+	value, err := lazyenv.Get("TEST_UINT_INVALID", lazyenv.Required[uintptr], lazyenv.Uintptr)
 	if err == nil {
 		t.Error("expected error, got nil")
 	}
@@ -559,3 +559,103 @@ func TestLazyMustGet_Exists(t *testing.T) {
 		t.Errorf("expected value to be %s, got instead: %s", "test", v)
 	}
 }
+
+func TestLazyGet_Strict(t *testing.T) {
+	os.Setenv("TEST_STRICT", "80a")
+	defer os.Unsetenv("TEST_STRICT")
+	value, err := lazyenv.Get("TEST_STRICT", lazyenv.OrReturn(8080), lazyenv.Strict(lazyenv.Int))
+	var parseErr *lazyenv.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected ParseError, got %v", err)
+	}
+	if parseErr.Key != "TEST_STRICT" || parseErr.Value != "80a" || parseErr.Err == nil {
+		t.Errorf("unexpected ParseError: %+v", parseErr)
+	}
+	if value != 0 {
+		t.Errorf("expected 0, got %d", value)
+	}
+}
+
+func TestLazyGet_Strict_Valid(t *testing.T) {
+	os.Setenv("TEST_STRICT_VALID", "80")
+	defer os.Unsetenv("TEST_STRICT_VALID")
+	value, err := lazyenv.Get("TEST_STRICT_VALID", lazyenv.OrReturn(8080), lazyenv.Strict(lazyenv.Int))
+	if err != nil {
+		t.Error(err)
+	}
+	if value != 80 {
+		t.Errorf("expected 80, got %d", value)
+	}
+}
+
+func TestLazyGet_Strict_Env(t *testing.T) {
+	env := lazyenv.New(lazyenv.WithStrict(), lazyenv.WithSource(lazyenv.Map{"PORT": "80a"}))
+	_, err := lazyenv.GetFrom(env, "PORT", lazyenv.Required[int], lazyenv.Int)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if err.Error() != `failed to parse PORT="80a": strconv.Atoi: parsing "80a": invalid syntax` {
+		t.Errorf("expected error, got %s", err)
+	}
+}
+
+func TestLazyGet_SetStrict(t *testing.T) {
+	lazyenv.SetStrict(true)
+	defer lazyenv.SetStrict(false)
+	os.Setenv("TEST_SET_STRICT", "zzz")
+	defer os.Unsetenv("TEST_SET_STRICT")
+	_, err := lazyenv.Get("TEST_SET_STRICT", lazyenv.Optional[int], lazyenv.Int)
+	var parseErr *lazyenv.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Errorf("expected ParseError, got %v", err)
+	}
+}
+
+func TestLazyMustGet_Strict_Panic(t *testing.T) {
+	os.Setenv("MUSTGET_TEST_STRICT", "zzz")
+	defer os.Unsetenv("MUSTGET_TEST_STRICT")
+	defer func() {
+		if _, ok := recover().(*lazyenv.ParseError); !ok {
+			t.Errorf("expected MustGet to panic with a ParseError")
+		}
+	}()
+	lazyenv.MustGet("MUSTGET_TEST_STRICT", lazyenv.Strict(lazyenv.Int))
+
+	t.Errorf("if this test made it this far, it did not panic as it was supposed to")
+}
+
+func TestLazyGet_DefaultValueParams_Missing(t *testing.T) {
+	var params lazyenv.GetDefaultValueParams
+	_, err := lazyenv.Get("TEST_DEFAULT_PARAMS_MISSING", func(p lazyenv.GetDefaultValueParams) (int, error) {
+		params = p
+		return 10, nil
+	}, lazyenv.Int)
+	if err != nil {
+		t.Error(err)
+	}
+	if params.Key != "TEST_DEFAULT_PARAMS_MISSING" || params.Reason != lazyenv.Missing || params.RawValue != "" || params.MapperError != nil {
+		t.Errorf("unexpected params: %+v", params)
+	}
+}
+
+func TestLazyGet_DefaultValueParams_ParseFailed(t *testing.T) {
+	os.Setenv("TEST_DEFAULT_PARAMS_INVALID", "abc")
+	defer os.Unsetenv("TEST_DEFAULT_PARAMS_INVALID")
+	var params lazyenv.GetDefaultValueParams
+	value, err := lazyenv.Get("TEST_DEFAULT_PARAMS_INVALID", func(p lazyenv.GetDefaultValueParams) (int, error) {
+		params = p
+		return 10, nil
+	}, lazyenv.Int)
+	if err != nil {
+		t.Error(err)
+	}
+	if value != 10 {
+		t.Errorf("expected 10, got %d", value)
+	}
+	if params.Reason != lazyenv.ParseFailed || params.RawValue != "abc" || params.MapperError == nil || params.Source != "env" {
+		t.Errorf("unexpected params: %+v", params)
+	}
+	if params.Reason.String() != "parse failed" {
+		t.Errorf("expected parse failed, got %s", params.Reason)
+	}
+}
@@ -0,0 +1,104 @@
+package lazyenv
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// URL is a mapper that parses absolute URLs such as postgres://user@localhost:5432/app
+func URL(value string) (*url.URL, error) {
+	result, err := url.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	if result.Scheme == "" {
+		return nil, fmt.Errorf("URL %q has no scheme", value)
+	}
+	return result, nil
+}
+
+// URLOf returns a mapper that works like URL, but only accepts the given schemes, compared case-insensitively
+func URLOf(schemes ...string) Mapper[*url.URL] {
+	return func(value string) (*url.URL, error) {
+		result, err := URL(value)
+		if err != nil {
+			return nil, err
+		}
+		for _, scheme := range schemes {
+			if strings.EqualFold(result.Scheme, scheme) {
+				return result, nil
+			}
+		}
+		return nil, fmt.Errorf("URL scheme %q is not one of %s", result.Scheme, strings.Join(schemes, ", "))
+	}
+}
+
+// IP is a mapper that parses IPv4 and IPv6 addresses such as 10.0.0.1 or ::1
+func IP(value string) (netip.Addr, error) {
+	return netip.ParseAddr(value)
+}
+
+// CIDR is a mapper that parses IP prefixes in CIDR notation such as 10.0.0.0/8, use SliceOf(",", CIDR) for lists
+func CIDR(value string) (netip.Prefix, error) {
+	return netip.ParsePrefix(value)
+}
+
+// MAC is a mapper that parses hardware addresses such as 00:00:5e:00:53:01 with net.ParseMAC
+func MAC(value string) (net.HardwareAddr, error) {
+	return net.ParseMAC(value)
+}
+
+// HostPort is an address made of a host and a numeric port, such as localhost:8080 or :8080. The host may be empty
+type HostPort struct {
+	Host string
+	Port uint16
+}
+
+func (h HostPort) String() string {
+	return net.JoinHostPort(h.Host, strconv.Itoa(int(h.Port)))
+}
+
+func (h HostPort) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+func (h *HostPort) UnmarshalText(text []byte) error {
+	result, err := ParseHostPort(string(text))
+	if err != nil {
+		return err
+	}
+	*h = result
+	return nil
+}
+
+// ParseHostPort is a mapper that parses host:port addresses, IPv6 hosts must be in brackets such as [::1]:8080
+func ParseHostPort(value string) (HostPort, error) {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		return HostPort{}, err
+	}
+	number, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return HostPort{}, fmt.Errorf("invalid port %q in %q", port, value)
+	}
+	return HostPort{host, uint16(number)}, nil
+}
+
+// HostPortIn returns a mapper that works like ParseHostPort, but only accepts ports between min and max, inclusive.
+// A port out of range is reported as with Range
+func HostPortIn(min uint16, max uint16) Mapper[HostPort] {
+	return func(value string) (HostPort, error) {
+		result, err := ParseHostPort(value)
+		if err != nil {
+			return HostPort{}, err
+		}
+		if result.Port < min || result.Port > max {
+			return HostPort{}, &strictError{fmt.Errorf("%w: port %d is not between %d and %d", strconv.ErrRange, result.Port, min, max)}
+		}
+		return result, nil
+	}
+}
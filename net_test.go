@@ -0,0 +1,113 @@
+package lazyenv_test
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+func TestLazyGet_URL(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"DATABASE_URL": "postgres://admin@localhost:5432/app?sslmode=disable"}))
+	value, err := lazyenv.GetFrom(env, "DATABASE_URL", lazyenv.Required[*url.URL], lazyenv.URLOf("postgres", "postgresql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.Hostname() != "localhost" || value.Port() != "5432" || value.User.Username() != "admin" || value.Path != "/app" {
+		t.Errorf("unexpected URL %s", value)
+	}
+}
+
+func TestLazyGet_URL_Invalid(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"RELATIVE": "/app", "INVALID": "http://[::1", "MYSQL": "mysql://localhost/app"}))
+	for _, key := range []string{"RELATIVE", "INVALID"} {
+		if _, err := lazyenv.GetFrom(env, key, lazyenv.Required[*url.URL], lazyenv.URL); err == nil {
+			t.Errorf("%s: expected error, got nil", key)
+		}
+	}
+	_, err := lazyenv.GetFrom(env, "MYSQL", lazyenv.Required[*url.URL], lazyenv.Strict(lazyenv.URLOf("postgres")))
+	if err == nil || err.Error() != `failed to parse MYSQL="mysql://localhost/app": URL scheme "mysql" is not one of postgres` {
+		t.Errorf("expected the scheme to be rejected, got %v", err)
+	}
+}
+
+func TestLazyGet_IP(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"V4": "10.0.0.1", "V6": "::1", "INVALID": "10.0.0.256"}))
+	if value := lazyenv.MustGetFrom(env, "V4", lazyenv.IP); value != netip.MustParseAddr("10.0.0.1") {
+		t.Errorf("expected 10.0.0.1, got %s", value)
+	}
+	if value := lazyenv.MustGetFrom(env, "V6", lazyenv.IP); !value.IsLoopback() {
+		t.Errorf("expected ::1, got %s", value)
+	}
+	if _, err := lazyenv.GetFrom(env, "INVALID", lazyenv.Required[netip.Addr], lazyenv.IP); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestLazyGet_CIDR(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"TRUSTED_PROXIES": "10.0.0.0/8,192.168.0.0/16", "INVALID": "10.0.0.0/33"}))
+	value := lazyenv.MustGetFrom(env, "TRUSTED_PROXIES", lazyenv.SliceOf(",", lazyenv.CIDR))
+	if len(value) != 2 || !value[1].Contains(netip.MustParseAddr("192.168.1.1")) {
+		t.Errorf("expected 2 prefixes, got %v", value)
+	}
+	if _, err := lazyenv.GetFrom(env, "INVALID", lazyenv.Required[netip.Prefix], lazyenv.CIDR); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestLazyGet_MAC(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"MAC": "00:00:5e:00:53:01", "INVALID": "00:00:5e"}))
+	if value := lazyenv.MustGetFrom(env, "MAC", lazyenv.MAC); value.String() != "00:00:5e:00:53:01" {
+		t.Errorf("expected 00:00:5e:00:53:01, got %s", value)
+	}
+	if _, err := lazyenv.GetFrom(env, "INVALID", lazyenv.Optional[net.HardwareAddr], lazyenv.Strict(lazyenv.MAC)); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestLazyGet_HostPort(t *testing.T) {
+	t.Parallel()
+	tests := map[string]lazyenv.HostPort{
+		"localhost:8080": {Host: "localhost", Port: 8080},
+		":0":             {Port: 0},
+		"[::1]:443":      {Host: "::1", Port: 443},
+	}
+	for input, expected := range tests {
+		env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"LISTEN_ADDR": input}))
+		value, err := lazyenv.GetFrom(env, "LISTEN_ADDR", lazyenv.Required[lazyenv.HostPort], lazyenv.ParseHostPort)
+		if err != nil {
+			t.Errorf("%s: %v", input, err)
+		}
+		if value != expected {
+			t.Errorf("%s: expected %+v, got %+v", input, expected, value)
+		}
+		if value.String() != input {
+			t.Errorf("expected %s to be formatted back, got %s", input, value)
+		}
+	}
+}
+
+func TestLazyGet_HostPort_Invalid(t *testing.T) {
+	t.Parallel()
+	for _, input := range []string{"localhost", "localhost:http", "localhost:65536", "localhost:-1", "::1:80", "localhost:70000"} {
+		env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"LISTEN_ADDR": input}))
+		_, err := lazyenv.GetFrom(env, "LISTEN_ADDR", lazyenv.Required[lazyenv.HostPort], lazyenv.HostPortIn(1024, 65535))
+		if err == nil || err.Error() != "required variable not found: LISTEN_ADDR" {
+			t.Errorf("%q: expected error, got %v", input, err)
+		}
+	}
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"LISTEN_ADDR": "localhost:80"}))
+	_, err := lazyenv.GetFrom(env, "LISTEN_ADDR", lazyenv.OrReturn(lazyenv.HostPort{}), lazyenv.HostPortIn(1024, 65535))
+	if !errors.Is(err, strconv.ErrRange) || err.Error() != `failed to parse LISTEN_ADDR="localhost:80": value out of range: port 80 is not between 1024 and 65535` {
+		t.Errorf("expected a port out of range not to fall back to the default, got %v", err)
+	}
+}
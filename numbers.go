@@ -0,0 +1,70 @@
+package lazyenv
+
+import (
+	"fmt"
+	"strconv"
+	"unsafe"
+)
+
+type integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+type number interface {
+	integer | ~float32 | ~float64
+}
+
+type ordered interface {
+	number | ~string
+}
+
+// Integer is a mapper that parses integers of any size in base 10, or in the base given by a prefix,
+// such as 0x1f, 0o644, 0644 or 0b101, and allows underscores between digits, such as 1_000_000, as Go does
+func Integer[T integer](value string) (T, error) {
+	var zero T
+	bits := int(unsafe.Sizeof(zero)) * 8
+	if signed := ^zero < 0; signed {
+		i64, err := strconv.ParseInt(value, 0, bits)
+		if err != nil {
+			return 0, err
+		}
+		return T(i64), nil
+	}
+	u64, err := strconv.ParseUint(value, 0, bits)
+	if err != nil {
+		return 0, err
+	}
+	return T(u64), nil
+}
+
+// Range wraps a mapper so that values outside min and max, inclusive, are rejected.
+// A value out of range is reported as a *ParseError naming the key, even if the Env is not strict, and matches strconv.ErrRange.
+// Values the wrapped mapper cannot parse are treated as usual
+func Range[T ordered](min T, max T, mapper Mapper[T]) Mapper[T] {
+	return func(value string) (T, error) {
+		result, err := mapper(value)
+		if err != nil {
+			return result, err
+		}
+		if result < min || result > max {
+			var zero T
+			return zero, &strictError{fmt.Errorf("%w: %v is not between %v and %v", strconv.ErrRange, result, min, max)}
+		}
+		return result, nil
+	}
+}
+
+// Positive wraps a mapper so that values that are zero or negative are rejected, reported as with Range
+func Positive[T number](mapper Mapper[T]) Mapper[T] {
+	return func(value string) (T, error) {
+		result, err := mapper(value)
+		if err != nil {
+			return result, err
+		}
+		if result <= 0 {
+			var zero T
+			return zero, &strictError{fmt.Errorf("%w: %v is not positive", strconv.ErrRange, result)}
+		}
+		return result, nil
+	}
+}
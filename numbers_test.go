@@ -0,0 +1,111 @@
+package lazyenv_test
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+func TestLazyGet_Integer(t *testing.T) {
+	t.Parallel()
+	tests := map[string]int64{
+		"42":        42,
+		"-42":       -42,
+		"0xff":      255,
+		"0o644":     420,
+		"0644":      420,
+		"0b101":     5,
+		"1_000_000": 1000000,
+	}
+	for input, expected := range tests {
+		env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"VALUE": input}))
+		value, err := lazyenv.GetFrom(env, "VALUE", lazyenv.Required[int64], lazyenv.Integer[int64])
+		if err != nil {
+			t.Errorf("%s: %v", input, err)
+		}
+		if value != expected {
+			t.Errorf("%s: expected %d, got %d", input, expected, value)
+		}
+	}
+}
+
+func TestLazyGet_Integer_Types(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"FILE_MODE": "0o644", "FLAGS": "0xff", "POINTER": "0x10"}))
+	if value := lazyenv.MustGetFrom(env, "FILE_MODE", lazyenv.Integer[os.FileMode]); value != 0644 {
+		t.Errorf("expected 0644, got %o", value)
+	}
+	if value := lazyenv.MustGetFrom(env, "FLAGS", lazyenv.Integer[uint8]); value != 255 {
+		t.Errorf("expected 255, got %d", value)
+	}
+	if value := lazyenv.MustGetFrom(env, "POINTER", lazyenv.Integer[uintptr]); value != 16 {
+		t.Errorf("expected 16, got %d", value)
+	}
+}
+
+func TestLazyGet_Integer_Invalid(t *testing.T) {
+	os.Setenv("TEST_INTEGER_INVALID", "0x100")
+	defer os.Unsetenv("TEST_INTEGER_INVALID")
+	value, err := lazyenv.Get("TEST_INTEGER_INVALID", lazyenv.Required[int8], lazyenv.Integer[int8])
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+	if err.Error() != "required variable not found: TEST_INTEGER_INVALID" {
+		t.Errorf("expected error, got %s", err)
+	}
+	if value != 0 {
+		t.Errorf("expected 0, got %d", value)
+	}
+	for _, input := range []string{"", "zzz", "-1", "0x", "1__0", "_1", "0o8", "256"} {
+		if value, err := lazyenv.Integer[uint8](input); err == nil {
+			t.Errorf("%q: expected error, got %d", input, value)
+		}
+	}
+}
+
+func TestLazyGet_Range(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"PORT": "8080", "HIGH": "70000", "RATIO": "1.5", "LEVEL": "c", "INVALID": "abc"}))
+	port := lazyenv.Range(1, 65535, lazyenv.Int)
+	if value := lazyenv.MustGetFrom(env, "PORT", port); value != 8080 {
+		t.Errorf("expected 8080, got %d", value)
+	}
+	_, err := lazyenv.GetFrom(env, "HIGH", lazyenv.OrReturn(80), port)
+	if !errors.Is(err, lazyenv.ErrParse) || !errors.Is(err, strconv.ErrRange) {
+		t.Fatalf("expected a *ParseError matching strconv.ErrRange, got %v", err)
+	}
+	if expected := `failed to parse HIGH="70000": value out of range: 70000 is not between 1 and 65535`; err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+	if _, err := lazyenv.GetFrom(env, "RATIO", lazyenv.Required[float64], lazyenv.Range(0, 1, lazyenv.Float64)); err == nil {
+		t.Error("expected error, got nil")
+	}
+	if _, err := lazyenv.GetFrom(env, "LEVEL", lazyenv.Required[string], lazyenv.Range("a", "b", lazyenv.String)); err == nil {
+		t.Error("expected error, got nil")
+	}
+	if value, err := lazyenv.GetFrom(env, "INVALID", lazyenv.OrReturn(80), port); err != nil || value != 80 {
+		t.Errorf("expected values that cannot be parsed to use the default, got %d, %v", value, err)
+	}
+}
+
+func TestLazyGet_Positive(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"WORKERS": "4", "ZERO": "0", "NEGATIVE": "-0.5"}))
+	if value := lazyenv.MustGetFrom(env, "WORKERS", lazyenv.Positive(lazyenv.Int)); value != 4 {
+		t.Errorf("expected 4, got %d", value)
+	}
+	_, err := lazyenv.GetFrom(env, "ZERO", lazyenv.Required[int], lazyenv.Positive(lazyenv.Int))
+	if err == nil || err.Error() != `failed to parse ZERO="0": value out of range: 0 is not positive` {
+		t.Errorf("expected an error naming ZERO, got %v", err)
+	}
+	_, err = lazyenv.GetFrom(env, "ZERO", lazyenv.OrReturn(1), lazyenv.Positive(lazyenv.Int))
+	if !errors.Is(err, lazyenv.ErrParse) || !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("expected a value that is not positive not to fall back to the default, got %v", err)
+	}
+	if _, err := lazyenv.GetFrom(env, "NEGATIVE", lazyenv.Required[float64], lazyenv.Positive(lazyenv.Float64)); !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("expected strconv.ErrRange, got %v", err)
+	}
+}
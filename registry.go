@@ -0,0 +1,169 @@
+package lazyenv
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// Spec declares a variable the program reads. Type is the name shown in the documentation, such as "int" or "duration",
+// Default is the value used when the variable is not set, as it would be written in the environment
+type Spec struct {
+	Key         string
+	Type        string
+	Description string
+	Default     string
+	Required    bool
+	Secret      bool
+}
+
+// Registry holds the declared variables of a program and renders their documentation
+type Registry struct {
+	mu    sync.Mutex
+	specs map[string]Spec
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{specs: make(map[string]Spec)}
+}
+
+var defaultRegistry = NewRegistry()
+
+// DefaultRegistry returns the Registry used by Declare
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Declare adds a variable to the default Registry
+func Declare(spec Spec) {
+	defaultRegistry.Declare(spec)
+}
+
+// Declare adds a variable to the Registry, replacing an earlier declaration of the same key
+func (r *Registry) Declare(spec Spec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.specs[spec.Key] = spec
+}
+
+// Specs returns the declared variables sorted by key
+func (r *Registry) Specs() []Spec {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	specs := make([]Spec, 0, len(r.specs))
+	for _, spec := range r.specs {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Key < specs[j].Key
+	})
+	return specs
+}
+
+// WriteMarkdown writes a Markdown table of the declared variables. Defaults of secrets are redacted
+func (r *Registry) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| Variable | Type | Required | Default | Description |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, spec := range r.Specs() {
+		required := "no"
+		if spec.Required {
+			required = "yes"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n", spec.Key, escapeMarkdown(spec.Type), required,
+			escapeMarkdown(markdownDefault(spec)), escapeMarkdown(spec.Description))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteUsage writes a plain text block listing the declared variables, suitable for the output of -help
+func (r *Registry) WriteUsage(w io.Writer) error {
+	if _, err := io.WriteString(w, "Environment variables:\n"); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, spec := range r.Specs() {
+		var notes []string
+		if spec.Required {
+			notes = append(notes, "required")
+		}
+		if spec.Default != "" && !spec.Secret {
+			notes = append(notes, "default "+spec.Default)
+		}
+		if spec.Secret {
+			notes = append(notes, "secret")
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", spec.Key, spec.Type, strings.Join(notes, ", "), singleLine(spec.Description))
+	}
+	return tw.Flush()
+}
+
+// WriteDotenv writes a .env.example file with a commented entry for each declared variable.
+// Required variables and variables with a default are set, the others are commented out. Defaults of secrets are left out
+func (r *Registry) WriteDotenv(w io.Writer) error {
+	var b strings.Builder
+	for i, spec := range r.Specs() {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		var notes []string
+		if spec.Type != "" {
+			notes = append(notes, spec.Type)
+		}
+		if spec.Required {
+			notes = append(notes, "required")
+		}
+		if spec.Secret {
+			notes = append(notes, "secret")
+		}
+		comment := singleLine(spec.Description)
+		if len(notes) > 0 {
+			comment = strings.TrimSpace(comment + " (" + strings.Join(notes, ", ") + ")")
+		}
+		if comment != "" {
+			fmt.Fprintf(&b, "# %s\n", comment)
+		}
+		value := spec.Default
+		if spec.Secret {
+			value = ""
+		}
+		if !spec.Required && value == "" {
+			b.WriteString("# ")
+		}
+		fmt.Fprintf(&b, "%s=%s\n", spec.Key, quoteDotenv(value))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func markdownDefault(spec Spec) string {
+	switch {
+	case spec.Secret && spec.Default != "":
+		return redacted
+	case spec.Default != "":
+		return "`" + spec.Default + "`"
+	}
+	return ""
+}
+
+func escapeMarkdown(text string) string {
+	return strings.ReplaceAll(singleLine(text), "|", `\|`)
+}
+
+func singleLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// quoteDotenv quotes a value if ParseDotenv would not read it back as it is
+func quoteDotenv(value string) string {
+	if value == "" || !strings.ContainsAny(value, " \t\r\n#\"'\\") {
+		return value
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(value) + `"`
+}
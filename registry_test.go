@@ -0,0 +1,96 @@
+package lazyenv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+func newTestRegistry() *lazyenv.Registry {
+	registry := lazyenv.NewRegistry()
+	registry.Declare(lazyenv.Spec{Key: "PORT", Type: "int", Description: "Port to listen on", Default: "8080"})
+	registry.Declare(lazyenv.Spec{Key: "DB_PASSWORD", Type: "string", Description: "Password of the database", Default: "hunter2", Required: true, Secret: true})
+	registry.Declare(lazyenv.Spec{Key: "GREETING", Type: "string", Description: "Shown | on the home page", Default: "hello world"})
+	registry.Declare(lazyenv.Spec{Key: "LOG_LEVEL", Type: "string"})
+	return registry
+}
+
+func TestRegistry_Specs(t *testing.T) {
+	t.Parallel()
+	registry := newTestRegistry()
+	registry.Declare(lazyenv.Spec{Key: "PORT", Type: "int", Default: "9090"})
+	specs := registry.Specs()
+	keys := make([]string, len(specs))
+	for i, spec := range specs {
+		keys[i] = spec.Key
+	}
+	if strings.Join(keys, ",") != "DB_PASSWORD,GREETING,LOG_LEVEL,PORT" {
+		t.Errorf("expected the specs sorted by key, got %v", keys)
+	}
+	if specs[3].Default != "9090" {
+		t.Errorf("expected the second declaration of PORT to replace the first, got %+v", specs[3])
+	}
+}
+
+func TestRegistry_WriteMarkdown(t *testing.T) {
+	t.Parallel()
+	var b strings.Builder
+	if err := newTestRegistry().WriteMarkdown(&b); err != nil {
+		t.Fatal(err)
+	}
+	expected := "| Variable | Type | Required | Default | Description |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| `DB_PASSWORD` | string | yes | [REDACTED] | Password of the database |\n" +
+		"| `GREETING` | string | no | `hello world` | Shown \\| on the home page |\n" +
+		"| `LOG_LEVEL` | string | no |  |  |\n" +
+		"| `PORT` | int | no | `8080` | Port to listen on |\n"
+	if b.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, b.String())
+	}
+}
+
+func TestRegistry_WriteUsage(t *testing.T) {
+	t.Parallel()
+	var b strings.Builder
+	if err := newTestRegistry().WriteUsage(&b); err != nil {
+		t.Fatal(err)
+	}
+	expected := "Environment variables:\n" +
+		"  DB_PASSWORD  string  required, secret     Password of the database\n" +
+		"  GREETING     string  default hello world  Shown | on the home page\n" +
+		"  LOG_LEVEL    string                       \n" +
+		"  PORT         int     default 8080         Port to listen on\n"
+	if b.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, b.String())
+	}
+}
+
+func TestRegistry_WriteDotenv(t *testing.T) {
+	t.Parallel()
+	var b strings.Builder
+	if err := newTestRegistry().WriteDotenv(&b); err != nil {
+		t.Fatal(err)
+	}
+	expected := "# Password of the database (string, required, secret)\n" +
+		"DB_PASSWORD=\n" +
+		"\n" +
+		"# Shown | on the home page (string)\n" +
+		"GREETING=\"hello world\"\n" +
+		"\n" +
+		"# (string)\n" +
+		"# LOG_LEVEL=\n" +
+		"\n" +
+		"# Port to listen on (int)\n" +
+		"PORT=8080\n"
+	if b.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, b.String())
+	}
+	values, err := lazyenv.ParseDotenv(strings.NewReader(b.String()), ".env.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["GREETING"] != "hello world" || values["PORT"] != "8080" {
+		t.Errorf("expected the defaults to be read back, got %v", values)
+	}
+}
@@ -0,0 +1,107 @@
+package lazyenv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+const redacted = "[REDACTED]"
+
+// Secret holds a value that must not end up in logs. Printing it with fmt, marshalling it to JSON or text
+// and errors about it all show [REDACTED] instead of the value, use Reveal to read it.
+// The value is kept behind a pointer, so that fmt only prints an address when it cannot call the methods of a Secret,
+// such as for an unexported field of a struct
+type Secret[T any] struct {
+	value *T
+}
+
+// NewSecret wraps a value in a Secret
+func NewSecret[T any](value T) Secret[T] {
+	return Secret[T]{&value}
+}
+
+// Reveal returns the wrapped value, or the zero value of T for the zero Secret
+func (s Secret[T]) Reveal() T {
+	if s.value == nil {
+		var zero T
+		return zero
+	}
+	return *s.value
+}
+
+func (s Secret[T]) String() string {
+	return redacted
+}
+
+func (s Secret[T]) GoString() string {
+	return redacted
+}
+
+func (s Secret[T]) Format(f fmt.State, verb rune) {
+	io.WriteString(f, redacted)
+}
+
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redacted)
+}
+
+func (s Secret[T]) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+func (Secret[T]) secret() {}
+
+// bindMapper lets Bind populate Secret fields using the mapper of the wrapped type
+func (Secret[T]) bindMapper(separator string) (Mapper[any], error) {
+	var zero T
+	inner, err := mapperFor(reflect.TypeOf(&zero).Elem(), separator)
+	if err != nil {
+		return nil, err
+	}
+	return anyMapper(SecretOf(func(value string) (T, error) {
+		result, err := inner(value)
+		if err != nil {
+			return zero, err
+		}
+		return result.(T), nil
+	})), nil
+}
+
+// SecretOf returns a mapper that wraps the result of the given mapper in a Secret.
+// If the mapper fails, the value is redacted from the error and from anything Get reports about it
+func SecretOf[T any](mapper Mapper[T]) Mapper[Secret[T]] {
+	return func(value string) (Secret[T], error) {
+		result, err := mapper(value)
+		if err != nil {
+			return Secret[T]{}, &secretError{err}
+		}
+		return NewSecret(result), nil
+	}
+}
+
+// secretError hides the message of the mapper error, which usually quotes the value or a part of it.
+// Only the reason of strconv errors, which never contains the value, is kept
+type secretError struct {
+	err error
+}
+
+func (e *secretError) Error() string {
+	var numErr *strconv.NumError
+	if errors.As(e.err, &numErr) {
+		return "invalid secret value: " + numErr.Err.Error()
+	}
+	return "invalid secret value"
+}
+
+func (e *secretError) Unwrap() error {
+	return e.err
+}
+
+func isSecret(v any) bool {
+	_, ok := v.(interface{ secret() })
+	return ok
+}
@@ -0,0 +1,130 @@
+package lazyenv_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+func TestSecret_Redacted(t *testing.T) {
+	t.Parallel()
+	secret := lazyenv.NewSecret("hunter2")
+	config := struct {
+		Password lazyenv.Secret[string] `json:"password"`
+	}{secret}
+	unexported := struct {
+		port  int
+		token lazyenv.Secret[string]
+	}{1, secret}
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%d"} {
+		if out := fmt.Sprintf(format, config); strings.Contains(out, "hunter2") {
+			t.Errorf("expected %s to redact the secret, got %s", format, out)
+		}
+		if out := fmt.Sprintf(format, unexported); strings.Contains(out, "hunter2") || strings.Contains(out, hex.EncodeToString([]byte("hunter2"))) {
+			t.Errorf("expected %s to hide the secret in an unexported field, got %s", format, out)
+		}
+	}
+	if value := (lazyenv.Secret[string]{}).Reveal(); value != "" {
+		t.Errorf("expected the zero Secret to reveal an empty string, got %s", value)
+	}
+	out, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"password":"[REDACTED]"}` {
+		t.Errorf("expected redacted JSON, got %s", out)
+	}
+	text, _ := secret.MarshalText()
+	if string(text) != "[REDACTED]" || secret.String() != "[REDACTED]" || secret.GoString() != "[REDACTED]" {
+		t.Error("expected text, String and GoString to be redacted")
+	}
+	if secret.Reveal() != "hunter2" {
+		t.Errorf("expected hunter2, got %s", secret.Reveal())
+	}
+}
+
+func TestSecret_SecretOf(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"API_KEY": "s3cr3t", "PIN": "1234"}))
+	key, err := lazyenv.GetFrom(env, "API_KEY", lazyenv.Required[lazyenv.Secret[string]], lazyenv.SecretOf(lazyenv.String))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Reveal() != "s3cr3t" {
+		t.Errorf("expected s3cr3t, got %s", key.Reveal())
+	}
+	pin := lazyenv.MustGetFrom(env, "PIN", lazyenv.SecretOf(lazyenv.Int))
+	if pin.Reveal() != 1234 {
+		t.Errorf("expected 1234, got %d", pin.Reveal())
+	}
+}
+
+func TestSecret_ParseError(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"PIN": "12ab"}))
+	for _, mapper := range []lazyenv.Mapper[lazyenv.Secret[int]]{
+		lazyenv.Strict(lazyenv.SecretOf(lazyenv.Int)),
+		lazyenv.SecretOf(lazyenv.Strict(lazyenv.Int)),
+	} {
+		_, err := lazyenv.GetFrom(env, "PIN", lazyenv.Required[lazyenv.Secret[int]], mapper)
+		if !errors.Is(err, lazyenv.ErrParse) {
+			t.Fatalf("expected ErrParse, got %v", err)
+		}
+		if strings.Contains(err.Error(), "12ab") {
+			t.Errorf("expected the value to be redacted, got %s", err)
+		}
+		var parseErr *lazyenv.ParseError
+		if errors.As(err, &parseErr); parseErr.Value != "[REDACTED]" {
+			t.Errorf("expected the value to be redacted, got %s", parseErr.Value)
+		}
+	}
+}
+
+func TestSecret_DefaultValueParams(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"PIN": "12ab"}))
+	_, err := lazyenv.GetFrom(env, "PIN", func(params lazyenv.GetDefaultValueParams) (lazyenv.Secret[int], error) {
+		if params.RawValue != "[REDACTED]" || strings.Contains(params.MapperError.Error(), "12ab") {
+			t.Errorf("expected params to be redacted, got %+v", params)
+		}
+		return lazyenv.NewSecret(0), nil
+	}, lazyenv.SecretOf(lazyenv.Int))
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSecret_Cast(t *testing.T) {
+	t.Parallel()
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"API_KEY": "s3cr3t"}))
+	_, err := lazyenv.GetFrom(env, "API_KEY", lazyenv.Required[lazyenv.Secret[string]])
+	if !errors.Is(err, lazyenv.ErrCast) || strings.Contains(err.Error(), "s3cr3t") {
+		t.Errorf("expected redacted ErrCast, got %v", err)
+	}
+}
+
+func TestSecret_Bind(t *testing.T) {
+	t.Parallel()
+	var config struct {
+		Password lazyenv.Secret[string] `env:"PASSWORD" required:"true"`
+		Pins     lazyenv.Secret[[]int]  `env:"PINS"`
+	}
+	env := lazyenv.New(lazyenv.WithStrict(), lazyenv.WithSource(lazyenv.Map{"PASSWORD": "hunter2", "PINS": "1,2"}))
+	if err := lazyenv.BindFrom(env, &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Password.Reveal() != "hunter2" || len(config.Pins.Reveal()) != 2 {
+		t.Errorf("unexpected config: %v %v", config.Password.Reveal(), config.Pins.Reveal())
+	}
+
+	env = lazyenv.New(lazyenv.WithStrict(), lazyenv.WithSource(lazyenv.Map{"PASSWORD": "hunter2", "PINS": "1,hunter3"}))
+	err := lazyenv.BindFrom(env, &config)
+	if err == nil || strings.Contains(err.Error(), "hunter3") {
+		t.Errorf("expected redacted error, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package lazyenv
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+var byteUnits = map[string]uint64{
+	"": 1, "b": 1,
+	"k": 1e3, "kb": 1e3, "ki": 1 << 10, "kib": 1 << 10,
+	"m": 1e6, "mb": 1e6, "mi": 1 << 20, "mib": 1 << 20,
+	"g": 1e9, "gb": 1e9, "gi": 1 << 30, "gib": 1 << 30,
+	"t": 1e12, "tb": 1e12, "ti": 1 << 40, "tib": 1 << 40,
+	"p": 1e15, "pb": 1e15, "pi": 1 << 50, "pib": 1 << 50,
+	"e": 1e18, "eb": 1e18, "ei": 1 << 60, "eib": 1 << 60,
+}
+
+// ByteSize is a mapper that parses sizes in bytes with an optional SI or IEC unit, such as 512, 10MB, 10M, 1.5GiB or 64Ki.
+// SI units are powers of 1000 and IEC units powers of 1024, units are case-insensitive. Fractions of a byte are truncated
+func ByteSize(value string) (uint64, error) {
+	number, unit := splitUnit(strings.TrimSpace(value))
+	multiplier, ok := byteUnits[strings.ToLower(strings.TrimSpace(unit))]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q in byte size %q", unit, value)
+	}
+	size, err := parseDecimal(number)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", value)
+	}
+	size.Mul(size, new(big.Rat).SetUint64(multiplier))
+	bytes := new(big.Int).Quo(size.Num(), size.Denom())
+	if !bytes.IsUint64() {
+		return 0, fmt.Errorf("byte size %q is out of range", value)
+	}
+	return bytes.Uint64(), nil
+}
+
+var quantitySuffixes = map[string]*big.Rat{
+	"n": big.NewRat(1, 1e9), "u": big.NewRat(1, 1e6), "m": big.NewRat(1, 1e3), "": big.NewRat(1, 1),
+	"k": big.NewRat(1e3, 1), "M": big.NewRat(1e6, 1), "G": big.NewRat(1e9, 1),
+	"T": big.NewRat(1e12, 1), "P": big.NewRat(1e15, 1), "E": big.NewRat(1e18, 1),
+	"Ki": big.NewRat(1<<10, 1), "Mi": big.NewRat(1<<20, 1), "Gi": big.NewRat(1<<30, 1),
+	"Ti": big.NewRat(1<<40, 1), "Pi": big.NewRat(1<<50, 1), "Ei": big.NewRat(1<<60, 1),
+}
+
+// Quantity is a mapper that parses Kubernetes quantities, such as 100m, 1.5Gi, 2k or 1e3.
+// Suffixes are case-sensitive as in Kubernetes, so 100m is 0.1 and 100M is 100000000
+func Quantity(value string) (float64, error) {
+	number, suffix := splitUnit(value)
+	sign := 1.0
+	if strings.HasPrefix(number, "+") || strings.HasPrefix(number, "-") {
+		if number[0] == '-' {
+			sign = -1
+		}
+		number = number[1:]
+	}
+	quantity, err := parseDecimal(number)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity %q", value)
+	}
+	multiplier, ok := quantitySuffixes[suffix]
+	if !ok {
+		multiplier, ok = quantityExponent(suffix)
+	}
+	if !ok {
+		return 0, fmt.Errorf("unknown suffix %q in quantity %q", suffix, value)
+	}
+	result, _ := quantity.Mul(quantity, multiplier).Float64()
+	if math.IsInf(result, 0) {
+		return 0, fmt.Errorf("quantity %q is out of range", value)
+	}
+	return sign * result, nil
+}
+
+// quantityExponent parses decimal exponents such as e3 or E-2
+func quantityExponent(suffix string) (*big.Rat, bool) {
+	if len(suffix) < 2 || (suffix[0] != 'e' && suffix[0] != 'E') {
+		return nil, false
+	}
+	exponent := strings.TrimLeft(suffix[1:], "+-")
+	if len(suffix)-len(exponent) > 2 || exponent == "" || len(exponent) > 3 || strings.Trim(exponent, "0123456789") != "" {
+		return nil, false
+	}
+	return new(big.Rat).SetString("1" + suffix)
+}
+
+// splitUnit splits a value into the leading number, made of signs, digits and dots, and the unit that follows it
+func splitUnit(value string) (string, string) {
+	i := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != '+' && r != '-'
+	})
+	if i < 0 {
+		return value, ""
+	}
+	return value[:i], value[i:]
+}
+
+// parseDecimal parses an unsigned decimal number such as 10, 1.5 or .5 exactly
+func parseDecimal(number string) (*big.Rat, error) {
+	digits := strings.Replace(number, ".", "", 1)
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return nil, fmt.Errorf("invalid number %q", number)
+	}
+	result, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return nil, fmt.Errorf("invalid number %q", number)
+	}
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package lazyenv_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+func TestLazyGet_ByteSize(t *testing.T) {
+	t.Parallel()
+	tests := map[string]uint64{
+		"512":                  512,
+		"512B":                 512,
+		"10MB":                 10000000,
+		"10M":                  10000000,
+		"10MiB":                10 << 20,
+		"10 mib":               10 << 20,
+		"64Ki":                 64 << 10,
+		"1.5GiB":               3 << 29,
+		"1.5KB":                1500,
+		"1.1KiB":               1126,
+		".5k":                  500,
+		"18446744073709551615": 18446744073709551615,
+		"15EiB":                15 << 60,
+	}
+	for input, expected := range tests {
+		env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"MAX_UPLOAD": input}))
+		value, err := lazyenv.GetFrom(env, "MAX_UPLOAD", lazyenv.Required[uint64], lazyenv.ByteSize)
+		if err != nil {
+			t.Errorf("%s: %v", input, err)
+		}
+		if value != expected {
+			t.Errorf("%s: expected %d, got %d", input, expected, value)
+		}
+	}
+}
+
+func TestLazyGet_ByteSize_Invalid(t *testing.T) {
+	os.Setenv("TEST_BYTE_SIZE_INVALID", "10XB")
+	defer os.Unsetenv("TEST_BYTE_SIZE_INVALID")
+	value, err := lazyenv.Get("TEST_BYTE_SIZE_INVALID", lazyenv.Required[uint64], lazyenv.ByteSize)
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+	if err.Error() != "required variable not found: TEST_BYTE_SIZE_INVALID" {
+		t.Errorf("expected error, got %s", err)
+	}
+	if value != 0 {
+		t.Errorf("expected 0, got %d", value)
+	}
+}
+
+func TestLazyGet_ByteSize_Errors(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"":                     `invalid byte size ""`,
+		"MB":                   `invalid byte size "MB"`,
+		"-1MB":                 `invalid byte size "-1MB"`,
+		"1.2.3MB":              `invalid byte size "1.2.3MB"`,
+		"1e3":                  `unknown unit "e3" in byte size "1e3"`,
+		"10XB":                 `unknown unit "XB" in byte size "10XB"`,
+		"16EiB":                `byte size "16EiB" is out of range`,
+		"18446744073709551616": `byte size "18446744073709551616" is out of range`,
+	}
+	for input, expected := range tests {
+		if _, err := lazyenv.ByteSize(input); err == nil || err.Error() != expected {
+			t.Errorf("%q: expected %q, got %v", input, expected, err)
+		}
+	}
+}
+
+func TestLazyGet_Quantity(t *testing.T) {
+	t.Parallel()
+	tests := map[string]float64{
+		"100m":  0.1,
+		"1.5Gi": 1.5 * (1 << 30),
+		"2k":    2000,
+		"100M":  1e8,
+		"1e3":   1000,
+		"5E-3":  0.005,
+		"1E":    1e18,
+		"-2":    -2,
+		"+.5":   0.5,
+		"250u":  0.00025,
+		"128Mi": 128 << 20,
+	}
+	for input, expected := range tests {
+		env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"CPU": input}))
+		value, err := lazyenv.GetFrom(env, "CPU", lazyenv.Required[float64], lazyenv.Quantity)
+		if err != nil {
+			t.Errorf("%s: %v", input, err)
+		}
+		if value != expected {
+			t.Errorf("%s: expected %v, got %v", input, expected, value)
+		}
+	}
+}
+
+func TestLazyGet_Quantity_Invalid(t *testing.T) {
+	t.Parallel()
+	for _, input := range []string{"", "m", "1MB", "1 Gi", "1.2.3", "1e", "1e+-3", "1e1000", "--1", "1KI"} {
+		if value, err := lazyenv.Quantity(input); err == nil {
+			t.Errorf("%q: expected error, got %v", input, value)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package lazyenv
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Source is where raw values are read from. Lookup returns the value of the given key and whether it was present
+type Source interface {
+	Lookup(key string) (string, bool)
+}
+
+// Enumerable is implemented by sources that can also list every key they hold
+type Enumerable interface {
+	Source
+	Keys() []string
+}
+
+type osSource struct{}
+
+// OS is the default source, it reads variables from the environment of the current process
+var OS Source = osSource{}
+
+func (osSource) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+func (osSource) String() string {
+	return "env"
+}
+
+func (osSource) Keys() []string {
+	environ := os.Environ()
+	keys := make([]string, 0, len(environ))
+	for _, kv := range environ {
+		key, _, _ := strings.Cut(kv, "=")
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Map is a source backed by a plain map, useful for tests and for values loaded from elsewhere
+type Map map[string]string
+
+func (m Map) Lookup(key string) (string, bool) {
+	value, exists := m[key]
+	return value, exists
+}
+
+func (m Map) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type namedSource struct {
+	name   string
+	source Source
+}
+
+// Named gives a source the name reported by Origin, e.g. the path of the file it was loaded from
+func Named(name string, source Source) Source {
+	return namedSource{name, source}
+}
+
+func (s namedSource) Lookup(key string) (string, bool) {
+	return s.source.Lookup(key)
+}
+
+func (s namedSource) TryLookup(key string) (string, bool, error) {
+	value, _, exists, err := lookupLayer(s.source, key)
+	return value, exists, err
+}
+
+func (s namedSource) Keys() []string {
+	return keysOf(s.source)
+}
+
+func (s namedSource) String() string {
+	return s.name
+}
+
+// Fallible is implemented by sources that can fail to read a value that is present, such as a file that cannot be read.
+// Env uses TryLookup instead of Lookup when it is available, so that Get returns the error
+type Fallible interface {
+	Source
+	TryLookup(key string) (string, bool, error)
+}
+
+// Layered is implemented by sources that are made up of other sources, LookupLayer also returns the layer that supplied the value,
+// or the layer that failed to read it
+type Layered interface {
+	Source
+	LookupLayer(key string) (string, Source, bool, error)
+}
+
+// Chain is a source that tries each of its layers in order and returns the first value found,
+// e.g. Chain{OS, local, base, defaults} lets the process environment override .env files, which override defaults
+type Chain []Source
+
+func (c Chain) Lookup(key string) (string, bool) {
+	value, _, exists, _ := c.LookupLayer(key)
+	return value, exists
+}
+
+// LookupLayer stops at the first layer that has the key or fails to read it
+func (c Chain) LookupLayer(key string) (string, Source, bool, error) {
+	for _, layer := range c {
+		value, source, exists, err := lookupLayer(layer, key)
+		if exists || err != nil {
+			return value, source, exists, err
+		}
+	}
+	return "", nil, false, nil
+}
+
+// Keys returns the keys of every layer that is Enumerable, without duplicates
+func (c Chain) Keys() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, layer := range c {
+		for _, key := range keysOf(layer) {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lookupLayer looks up key using the richest interface the source implements
+func lookupLayer(source Source, key string) (string, Source, bool, error) {
+	switch s := source.(type) {
+	case Layered:
+		return s.LookupLayer(key)
+	case Fallible:
+		value, exists, err := s.TryLookup(key)
+		return value, source, exists, err
+	}
+	value, exists := source.Lookup(key)
+	return value, source, exists, nil
+}
+
+func keysOf(source Source) []string {
+	if enumerable, ok := source.(Enumerable); ok {
+		return enumerable.Keys()
+	}
+	return nil
+}
+
+// nameOf returns the name of a source as reported by Origin
+func nameOf(source Source) string {
+	if stringer, ok := source.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprintf("%T", source)
+}
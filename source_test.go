@@ -0,0 +1,96 @@
+package lazyenv_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+func TestSource_Map(t *testing.T) {
+	lazyenv.SetSource(lazyenv.Map{"TEST_SOURCE_MAP": "42"})
+	defer lazyenv.SetSource(lazyenv.OS)
+	value, err := lazyenv.Get("TEST_SOURCE_MAP", lazyenv.Required[int], lazyenv.Int)
+	if err != nil {
+		t.Error(err)
+	}
+	if value != 42 {
+		t.Errorf("expected 42, got %d", value)
+	}
+}
+
+func TestSource_Map_Missing(t *testing.T) {
+	os.Setenv("TEST_SOURCE_MAP_MISSING", "from os")
+	defer os.Unsetenv("TEST_SOURCE_MAP_MISSING")
+	lazyenv.SetSource(lazyenv.Map{})
+	defer lazyenv.SetSource(lazyenv.OS)
+	_, err := lazyenv.Get("TEST_SOURCE_MAP_MISSING", lazyenv.Required[string])
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestSource_Map_Keys(t *testing.T) {
+	keys := lazyenv.Map{"B": "2", "A": "1", "C": "3"}.Keys()
+	if len(keys) != 3 || keys[0] != "A" || keys[1] != "B" || keys[2] != "C" {
+		t.Errorf("expected [A B C], got %v", keys)
+	}
+}
+
+func TestSource_OS_Keys(t *testing.T) {
+	os.Setenv("TEST_SOURCE_OS_KEYS", "1")
+	defer os.Unsetenv("TEST_SOURCE_OS_KEYS")
+	for _, key := range lazyenv.OS.(lazyenv.Enumerable).Keys() {
+		if key == "TEST_SOURCE_OS_KEYS" {
+			return
+		}
+	}
+	t.Error("expected TEST_SOURCE_OS_KEYS to be listed")
+}
+
+func TestSource_Chain(t *testing.T) {
+	t.Parallel()
+	chain := lazyenv.Chain{
+		lazyenv.Named("process", lazyenv.Map{"A": "process"}),
+		lazyenv.Named(".env.local", lazyenv.Map{"A": "local", "B": "local"}),
+		lazyenv.Named(".env", lazyenv.Map{"A": "base", "B": "base", "C": "base"}),
+		lazyenv.Named("defaults", lazyenv.Map{"D": "default"}),
+	}
+	env := lazyenv.New(lazyenv.WithSource(chain))
+	for key, expected := range map[string]string{"A": "process", "B": ".env.local", "C": ".env", "D": "defaults"} {
+		if _, err := lazyenv.GetFrom(env, key, lazyenv.Required[string]); err != nil {
+			t.Error(err)
+		}
+		origin, ok := env.Origin(key)
+		if !ok || origin != expected {
+			t.Errorf("expected %s to come from %s, got %s", key, expected, origin)
+		}
+	}
+	if value := lazyenv.MustGetFrom[string](env, "B"); value != "local" {
+		t.Errorf("expected local, got %s", value)
+	}
+	if _, ok := env.Origin("MISSING"); ok {
+		t.Error("expected no origin for a missing key")
+	}
+	keys := chain.Keys()
+	if len(keys) != 4 || keys[0] != "A" || keys[3] != "D" {
+		t.Errorf("expected [A B C D], got %v", keys)
+	}
+}
+
+func TestSource_Chain_Nested(t *testing.T) {
+	t.Parallel()
+	inner := lazyenv.Chain{lazyenv.Named("inner", lazyenv.Map{"A": "1"})}
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Chain{lazyenv.Map{}, inner}))
+	if origin, _ := env.Origin("A"); origin != "inner" {
+		t.Errorf("expected inner, got %s", origin)
+	}
+}
+
+func TestSource_OS_Origin(t *testing.T) {
+	os.Setenv("TEST_SOURCE_OS_ORIGIN", "1")
+	defer os.Unsetenv("TEST_SOURCE_OS_ORIGIN")
+	if origin, _ := lazyenv.Origin("TEST_SOURCE_OS_ORIGIN"); origin != "env" {
+		t.Errorf("expected env, got %s", origin)
+	}
+}
@@ -0,0 +1,81 @@
+package lazyenv
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a mapper that parses durations such as 30s, 1m30s or 250ms with time.ParseDuration
+func Duration(value string) (time.Duration, error) {
+	return time.ParseDuration(value)
+}
+
+// DurationIn returns a mapper that works like Duration, but also accepts bare integers counted in unit,
+// so that with time.Second, HTTP_TIMEOUT=30 is 30s
+func DurationIn(unit time.Duration) Mapper[time.Duration] {
+	return func(value string) (time.Duration, error) {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.ParseDuration(value)
+		}
+		if unit > 0 && (n > math.MaxInt64/int64(unit) || n < math.MinInt64/int64(unit)) {
+			return 0, fmt.Errorf("duration %d%s is out of range", n, unitName(unit))
+		}
+		return time.Duration(n) * unit, nil
+	}
+}
+
+func unitName(unit time.Duration) string {
+	return strings.TrimPrefix(unit.String(), "1")
+}
+
+// Time is a mapper that parses RFC 3339 timestamps such as 2006-01-02T15:04:05Z, with optional fractional seconds
+func Time(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339, value)
+}
+
+// TimeOf returns a mapper that parses times with the first of the given layouts that matches, or RFC 3339 if there are none
+func TimeOf(layouts ...string) Mapper[time.Time] {
+	if len(layouts) == 0 {
+		return Time
+	}
+	return func(value string) (time.Time, error) {
+		for _, layout := range layouts {
+			if result, err := time.Parse(layout, value); err == nil {
+				return result, nil
+			}
+		}
+		if len(layouts) == 1 {
+			return time.Parse(layouts[0], value)
+		}
+		return time.Time{}, fmt.Errorf("cannot parse %q with any of the layouts %q", value, layouts)
+	}
+}
+
+// Unix is a mapper that parses Unix timestamps in seconds, with an optional fraction, such as 1700000000 or 1700000000.25
+func Unix(value string) (time.Time, error) {
+	seconds, fraction, hasFraction := strings.Cut(value, ".")
+	sec, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !hasFraction {
+		return time.Unix(sec, 0), nil
+	}
+	if fraction == "" || len(fraction) > 9 || strings.IndexFunc(fraction, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return time.Time{}, fmt.Errorf("invalid fraction of a second in %q", value)
+	}
+	nsec, _ := strconv.ParseInt(fraction+strings.Repeat("0", 9-len(fraction)), 10, 64)
+	if strings.HasPrefix(seconds, "-") {
+		nsec = -nsec
+	}
+	return time.Unix(sec, nsec), nil
+}
+
+// Location is a mapper that loads a time zone such as Europe/London or UTC with time.LoadLocation
+func Location(value string) (*time.Location, error) {
+	return time.LoadLocation(value)
+}
@@ -0,0 +1,171 @@
+package lazyenv_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielkov/lazyenv"
+)
+
+func TestLazyGet_Duration(t *testing.T) {
+	os.Setenv("TEST_DURATION", "1m30s")
+	defer os.Unsetenv("TEST_DURATION")
+	value, err := lazyenv.Get("TEST_DURATION", lazyenv.Required[time.Duration], lazyenv.Duration)
+	if err != nil {
+		t.Error(err)
+	}
+	if value != 90*time.Second {
+		t.Errorf("expected 1m30s, got %s", value)
+	}
+}
+
+func TestLazyGet_Duration_Invalid(t *testing.T) {
+	os.Setenv("TEST_DURATION_INVALID", "30")
+	defer os.Unsetenv("TEST_DURATION_INVALID")
+	value, err := lazyenv.Get("TEST_DURATION_INVALID", lazyenv.Required[time.Duration], lazyenv.Duration)
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+	if err.Error() != "required variable not found: TEST_DURATION_INVALID" {
+		t.Errorf("expected error, got %s", err)
+	}
+	if value != 0 {
+		t.Errorf("expected 0, got %s", value)
+	}
+}
+
+func TestLazyGet_DurationIn(t *testing.T) {
+	t.Parallel()
+	tests := map[string]time.Duration{
+		"30":    30 * time.Second,
+		"-5":    -5 * time.Second,
+		"250ms": 250 * time.Millisecond,
+		"0":     0,
+	}
+	for input, expected := range tests {
+		env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"TIMEOUT": input}))
+		value, err := lazyenv.GetFrom(env, "TIMEOUT", lazyenv.Required[time.Duration], lazyenv.DurationIn(time.Second))
+		if err != nil {
+			t.Errorf("%s: %v", input, err)
+		}
+		if value != expected {
+			t.Errorf("%s: expected %s, got %s", input, expected, value)
+		}
+	}
+}
+
+func TestLazyGet_DurationIn_Invalid(t *testing.T) {
+	t.Parallel()
+	for _, input := range []string{"", "abc", "1.5", "9223372036854775807", "10000000000000"} {
+		env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"TIMEOUT": input}))
+		value, err := lazyenv.GetFrom(env, "TIMEOUT", lazyenv.Required[time.Duration], lazyenv.Strict(lazyenv.DurationIn(time.Second)))
+		if err == nil {
+			t.Errorf("%q: expected error, got %s", input, value)
+		}
+	}
+}
+
+func TestLazyGet_Time(t *testing.T) {
+	os.Setenv("TEST_TIME", "2024-03-01T12:30:00.5+02:00")
+	defer os.Unsetenv("TEST_TIME")
+	value, err := lazyenv.Get("TEST_TIME", lazyenv.Required[time.Time], lazyenv.Time)
+	if err != nil {
+		t.Error(err)
+	}
+	if expected := time.Date(2024, 3, 1, 10, 30, 0, 5e8, time.UTC); !value.Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, value)
+	}
+}
+
+func TestLazyGet_Time_Invalid(t *testing.T) {
+	os.Setenv("TEST_TIME_INVALID", "2024-03-01")
+	defer os.Unsetenv("TEST_TIME_INVALID")
+	value, err := lazyenv.Get("TEST_TIME_INVALID", lazyenv.Required[time.Time], lazyenv.Time)
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+	if err.Error() != "required variable not found: TEST_TIME_INVALID" {
+		t.Errorf("expected error, got %s", err)
+	}
+	if !value.IsZero() {
+		t.Errorf("expected the zero time, got %s", value)
+	}
+}
+
+func TestLazyGet_TimeOf(t *testing.T) {
+	t.Parallel()
+	mapper := lazyenv.TimeOf("2006-01-02", time.RFC1123)
+	env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"DATE": "2024-03-01", "HTTP_DATE": "Fri, 01 Mar 2024 12:00:00 UTC", "INVALID": "yesterday"}))
+	if value := lazyenv.MustGetFrom(env, "DATE", mapper); !value.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected 2024-03-01, got %s", value)
+	}
+	if value := lazyenv.MustGetFrom(env, "HTTP_DATE", mapper); !value.Equal(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected 2024-03-01 12:00, got %s", value)
+	}
+	if _, err := lazyenv.GetFrom(env, "INVALID", lazyenv.Required[time.Time], lazyenv.Strict(mapper)); err == nil {
+		t.Error("expected error, got nil")
+	}
+	rfc3339 := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"TIME": "2024-03-01T12:00:00Z"}))
+	if value := lazyenv.MustGetFrom(rfc3339, "TIME", lazyenv.TimeOf()); !value.Equal(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected RFC 3339 to be the default layout, got %s", value)
+	}
+}
+
+func TestLazyGet_Unix(t *testing.T) {
+	t.Parallel()
+	tests := map[string]time.Time{
+		"1700000000":    time.Unix(1700000000, 0),
+		"1700000000.25": time.Unix(1700000000, 25e7),
+		"-1.5":          time.Unix(-2, 5e8),
+		"0":             time.Unix(0, 0),
+	}
+	for input, expected := range tests {
+		env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"STARTED_AT": input}))
+		value, err := lazyenv.GetFrom(env, "STARTED_AT", lazyenv.Required[time.Time], lazyenv.Unix)
+		if err != nil {
+			t.Errorf("%s: %v", input, err)
+		}
+		if !value.Equal(expected) {
+			t.Errorf("%s: expected %s, got %s", input, expected, value)
+		}
+	}
+}
+
+func TestLazyGet_Unix_Invalid(t *testing.T) {
+	t.Parallel()
+	for _, input := range []string{"", "abc", "1.", "1.2.3", "1.1234567891", "1.-5", "2024-03-01T00:00:00Z"} {
+		env := lazyenv.New(lazyenv.WithSource(lazyenv.Map{"STARTED_AT": input}))
+		_, err := lazyenv.GetFrom(env, "STARTED_AT", lazyenv.Required[time.Time], lazyenv.Unix)
+		if err == nil || err.Error() != "required variable not found: STARTED_AT" {
+			t.Errorf("%q: expected error, got %v", input, err)
+		}
+	}
+}
+
+func TestLazyGet_Location(t *testing.T) {
+	os.Setenv("TEST_LOCATION", "Europe/London")
+	defer os.Unsetenv("TEST_LOCATION")
+	value, err := lazyenv.Get("TEST_LOCATION", lazyenv.Required[*time.Location], lazyenv.Location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.String() != "Europe/London" {
+		t.Errorf("expected Europe/London, got %s", value)
+	}
+}
+
+func TestLazyGet_Location_Invalid(t *testing.T) {
+	os.Setenv("TEST_LOCATION_INVALID", "Mars/Olympus_Mons")
+	defer os.Unsetenv("TEST_LOCATION_INVALID")
+	value, err := lazyenv.Get("TEST_LOCATION_INVALID", lazyenv.Required[*time.Location], lazyenv.Location)
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+	if err.Error() != "required variable not found: TEST_LOCATION_INVALID" {
+		t.Errorf("expected error, got %s", err)
+	}
+	if value != nil {
+		t.Errorf("expected nil, got %s", value)
+	}
+}
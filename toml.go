@@ -0,0 +1,427 @@
+package lazyenv
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseTOML reads a TOML document from r and flattens it into env style keys, see Flatten. name is only used to report errors.
+// Tables, arrays of tables, dotted keys, all four kinds of strings, arrays and inline tables are supported.
+// Numbers, booleans and dates are kept as they are written, apart from underscores in numbers, which are removed
+func ParseTOML(r io.Reader, name string, separator string) (Map, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := tomlParser{name: name, src: strings.ReplaceAll(string(content), "\r\n", "\n")}
+	document, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return flattenFile(name, document, separator)
+}
+
+// LoadTOML reads and flattens the TOML file at path
+func LoadTOML(path string, separator string) (Map, error) {
+	return loadConfig(path, separator, ParseTOML)
+}
+
+type tomlParser struct {
+	name string
+	src  string
+	pos  int
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	line := strings.Count(p.src[:p.pos], "\n") + 1
+	return &SyntaxError{p.name, line, fmt.Sprintf(format, args...)}
+}
+
+func (p *tomlParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *tomlParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// skipSpace skips spaces and tabs, and also newlines and comments if multiline is set
+func (p *tomlParser) skipSpace(multiline bool) {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t':
+			p.pos++
+		case multiline && c == '\n':
+			p.pos++
+		case multiline && c == '#':
+			p.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) skipComment() {
+	for !p.eof() && p.peek() != '\n' {
+		p.pos++
+	}
+}
+
+// endOfLine expects only whitespace and an optional comment until the end of the line
+func (p *tomlParser) endOfLine() error {
+	p.skipSpace(false)
+	if p.peek() == '#' {
+		p.skipComment()
+	}
+	if !p.eof() && p.peek() != '\n' {
+		return p.errorf("expected the end of the line, found %q", p.src[p.pos:p.pos+1])
+	}
+	return nil
+}
+
+func (p *tomlParser) parse() (map[string]any, error) {
+	root := make(map[string]any)
+	current := root
+	for {
+		p.skipSpace(true)
+		if p.eof() {
+			return root, nil
+		}
+		if p.peek() == '[' {
+			table, err := p.parseTableHeader(root)
+			if err != nil {
+				return nil, err
+			}
+			current = table
+		} else {
+			if err := p.parseKeyValue(current); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.endOfLine(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *tomlParser) parseTableHeader(root map[string]any) (map[string]any, error) {
+	array := strings.HasPrefix(p.src[p.pos:], "[[")
+	if array {
+		p.pos += 2
+	} else {
+		p.pos++
+	}
+	p.skipSpace(false)
+	path, err := p.parseKey()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace(false)
+	closing := "]"
+	if array {
+		closing = "]]"
+	}
+	if !strings.HasPrefix(p.src[p.pos:], closing) {
+		return nil, p.errorf("expected %s", closing)
+	}
+	p.pos += len(closing)
+	table := root
+	for _, part := range path[:len(path)-1] {
+		if table, err = p.descend(table, part); err != nil {
+			return nil, err
+		}
+	}
+	last := path[len(path)-1]
+	if array {
+		list, ok := table[last].([]any)
+		if _, exists := table[last]; exists && !ok {
+			return nil, p.errorf("%s is not an array of tables", strings.Join(path, "."))
+		}
+		next := make(map[string]any)
+		table[last] = append(list, next)
+		return next, nil
+	}
+	return p.descend(table, last)
+}
+
+// descend returns the table stored under key, creating it if needed. For arrays of tables, the last table is returned
+func (p *tomlParser) descend(table map[string]any, key string) (map[string]any, error) {
+	switch child := table[key].(type) {
+	case nil:
+		next := make(map[string]any)
+		table[key] = next
+		return next, nil
+	case map[string]any:
+		return child, nil
+	case []any:
+		if len(child) > 0 {
+			if last, ok := child[len(child)-1].(map[string]any); ok {
+				return last, nil
+			}
+		}
+	}
+	return nil, p.errorf("key %s is already defined as a value", key)
+}
+
+func (p *tomlParser) parseKeyValue(table map[string]any) error {
+	path, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipSpace(false)
+	if p.peek() != '=' {
+		return p.errorf("expected = after key %s", strings.Join(path, "."))
+	}
+	p.pos++
+	p.skipSpace(false)
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	for _, part := range path[:len(path)-1] {
+		if table, err = p.descend(table, part); err != nil {
+			return err
+		}
+	}
+	last := path[len(path)-1]
+	if _, exists := table[last]; exists {
+		return p.errorf("duplicate key %s", strings.Join(path, "."))
+	}
+	table[last] = value
+	return nil
+}
+
+// parseKey parses a possibly dotted key made of bare and quoted parts
+func (p *tomlParser) parseKey() ([]string, error) {
+	var path []string
+	for {
+		p.skipSpace(false)
+		var part string
+		switch c := p.peek(); {
+		case c == '"' || c == '\'':
+			value, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			part = value
+		default:
+			start := p.pos
+			for !p.eof() && isTOMLBareKey(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("expected a key")
+			}
+			part = p.src[start:p.pos]
+		}
+		path = append(path, part)
+		p.skipSpace(false)
+		if p.peek() != '.' {
+			return path, nil
+		}
+		p.pos++
+	}
+}
+
+func isTOMLBareKey(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' || c == '-'
+}
+
+func (p *tomlParser) parseValue() (any, error) {
+	switch c := p.peek(); {
+	case c == '"' || c == '\'':
+		return p.parseString()
+	case c == '[':
+		return p.parseArray()
+	case c == '{':
+		return p.parseInlineTable()
+	}
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(" \t\n#,]}", rune(p.peek())) {
+		p.pos++
+	}
+	// local date times may contain a single space between the date and the time
+	if p.peek() == ' ' && p.pos+1 < len(p.src) && p.src[p.pos+1] >= '0' && p.src[p.pos+1] <= '9' && strings.Count(p.src[start:p.pos], "-") == 2 {
+		p.pos++
+		for !p.eof() && !strings.ContainsRune(" \t\n#,]}", rune(p.peek())) {
+			p.pos++
+		}
+	}
+	token := p.src[start:p.pos]
+	switch {
+	case token == "":
+		return nil, p.errorf("expected a value")
+	case token == "true" || token == "false" || token == "inf" || token == "+inf" || token == "-inf" || token == "nan" || token == "+nan" || token == "-nan":
+		return token, nil
+	case token[0] >= '0' && token[0] <= '9', token[0] == '+', token[0] == '-':
+		if strings.Contains(token, "_") {
+			token = strings.ReplaceAll(token, "_", "")
+		}
+		return token, nil
+	}
+	return nil, p.errorf("invalid value %q", token)
+}
+
+func (p *tomlParser) parseArray() ([]any, error) {
+	p.pos++
+	var items []any
+	for {
+		p.skipSpace(true)
+		if p.peek() == ']' {
+			p.pos++
+			return items, nil
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, value)
+		p.skipSpace(true)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, p.errorf("expected , or ] in array")
+		}
+	}
+}
+
+func (p *tomlParser) parseInlineTable() (map[string]any, error) {
+	p.pos++
+	table := make(map[string]any)
+	p.skipSpace(false)
+	if p.peek() == '}' {
+		p.pos++
+		return table, nil
+	}
+	for {
+		if err := p.parseKeyValue(table); err != nil {
+			return nil, err
+		}
+		p.skipSpace(false)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return table, nil
+		default:
+			return nil, p.errorf("expected , or } in inline table")
+		}
+	}
+}
+
+func (p *tomlParser) parseString() (string, error) {
+	rest := p.src[p.pos:]
+	switch {
+	case strings.HasPrefix(rest, `"""`):
+		return p.parseDelimited(`"""`, true, true)
+	case strings.HasPrefix(rest, `'''`):
+		return p.parseDelimited(`'''`, true, false)
+	case rest[0] == '"':
+		return p.parseDelimited(`"`, false, true)
+	}
+	return p.parseDelimited(`'`, false, false)
+}
+
+// parseDelimited parses a string between the given delimiters, resolving escape sequences in basic strings
+func (p *tomlParser) parseDelimited(delimiter string, multiline bool, basic bool) (string, error) {
+	start := p.pos
+	p.pos += len(delimiter)
+	if multiline && p.peek() == '\n' {
+		// a newline right after the opening delimiter is trimmed
+		p.pos++
+	}
+	var b strings.Builder
+	for {
+		if p.eof() || (!multiline && p.peek() == '\n') {
+			p.pos = start
+			return "", p.errorf("unterminated string")
+		}
+		if strings.HasPrefix(p.src[p.pos:], delimiter) {
+			end := p.pos + len(delimiter)
+			if multiline {
+				// up to two quotes right before the closing delimiter belong to the string
+				for end < len(p.src) && p.src[end] == delimiter[0] && end-p.pos < len(delimiter)+2 {
+					b.WriteByte(delimiter[0])
+					end++
+				}
+			}
+			p.pos = end
+			return b.String(), nil
+		}
+		c := p.peek()
+		if c != '\\' || !basic {
+			b.WriteByte(c)
+			p.pos++
+			continue
+		}
+		if err := p.parseEscape(&b, multiline); err != nil {
+			return "", err
+		}
+	}
+}
+
+func (p *tomlParser) parseEscape(b *strings.Builder, multiline bool) error {
+	p.pos++
+	if p.eof() {
+		return p.errorf("unterminated escape sequence")
+	}
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case 'e':
+		b.WriteByte(0x1b)
+	case '"', '\\':
+		b.WriteByte(c)
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+		if p.pos+size > len(p.src) {
+			return p.errorf("invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(p.src[p.pos:p.pos+size], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorf("invalid unicode escape \\%c%s", c, p.src[p.pos:p.pos+size])
+		}
+		b.WriteRune(rune(code))
+		p.pos += size
+	case ' ', '\t', '\n':
+		if !multiline {
+			return p.errorf("invalid escape sequence \\%c", c)
+		}
+		// a backslash at the end of a line trims the newline and the whitespace that follows it
+		p.pos--
+		for !p.eof() && strings.ContainsRune(" \t", rune(p.peek())) {
+			p.pos++
+		}
+		if p.peek() != '\n' {
+			return p.errorf("invalid escape sequence \\%c", c)
+		}
+		for !p.eof() && strings.ContainsRune(" \t\n", rune(p.peek())) {
+			p.pos++
+		}
+	default:
+		return p.errorf("invalid escape sequence \\%c", c)
+	}
+	return nil
+}
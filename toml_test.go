@@ -0,0 +1,97 @@
+package lazyenv_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+const tomlFixture = `# service configuration
+name = "app" # inline comment
+debug = true
+size = 1_000
+mode = 0o644
+literal = 'C:\path'
+escaped = "tab\there \u00e9"
+multi = """
+first \
+  second"""
+raw = '''
+line "one"
+line two'''
+quotes = """a ""quoted"" word"""""
+started = 1979-05-27 07:32:00
+site."google.com" = "search"
+ports = [
+  80, # http
+  443,
+]
+point = { x = 1, y = 2 }
+
+[database]
+host = "localhost"
+pool-size = 5
+
+[database.replica]
+host = "replica"
+
+[[servers]]
+host = "a"
+
+[[servers]]
+host = "b"
+`
+
+func TestTOML(t *testing.T) {
+	t.Parallel()
+	values, err := lazyenv.ParseTOML(strings.NewReader(tomlFixture), "config.toml", "_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectValues(t, values, map[string]string{
+		"NAME":                  "app",
+		"DEBUG":                 "true",
+		"SIZE":                  "1000",
+		"MODE":                  "0o644",
+		"LITERAL":               `C:\path`,
+		"ESCAPED":               "tab\there é",
+		"MULTI":                 "first second",
+		"RAW":                   "line \"one\"\nline two",
+		"QUOTES":                `a ""quoted"" word""`,
+		"STARTED":               "1979-05-27 07:32:00",
+		"SITE_GOOGLE_COM":       "search",
+		"PORTS":                 "80,443",
+		"POINT_X":               "1",
+		"POINT_Y":               "2",
+		"DATABASE_HOST":         "localhost",
+		"DATABASE_POOL_SIZE":    "5",
+		"DATABASE_REPLICA_HOST": "replica",
+		"SERVERS":               `[{"host":"a"},{"host":"b"}]`,
+	})
+}
+
+func TestTOML_Errors(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		input string
+		line  int
+	}{
+		{"a = 1\nb = \n", 2},
+		{"a = 1\na = 2\n", 2},
+		{"a = \"unterminated\nb = 1\n", 1},
+		{"a = 1 b = 2\n", 1},
+		{"[table\na = 1\n", 1},
+		{"a = 1\n[a]\n", 2},
+		{"a = \"\\q\"\n", 1},
+		{"a = [1 2]\n", 1},
+	}
+	for _, c := range cases {
+		_, err := lazyenv.ParseTOML(strings.NewReader(c.input), "config.toml", "_")
+		var syntaxErr *lazyenv.SyntaxError
+		if !errors.As(err, &syntaxErr) || syntaxErr.Line != c.line {
+			t.Errorf("expected a syntax error on line %d for %q, got %v", c.line, c.input, err)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package lazyenv
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Var is a typed handle to a variable, declared once at package level like a flag:
+//
+//	var Port = lazyenv.IntVar("PORT", 8080, "HTTP port")
+//
+// The value is read from the default Env the first time it is needed and kept until the Env is reset or replaced
+type Var[T any] struct {
+	spec         Spec
+	defaultValue T
+	getDefault   GetDefaultValue[T]
+	mapper       Mapper[T]
+
+	mu       sync.RWMutex
+	resolved bool
+	env      *Env
+	version  uint64
+	value    T
+	err      error
+}
+
+// Define declares a variable that falls back to defaultValue when it is not set, and adds it to the default Registry
+func Define[T any](key string, defaultValue T, description string, mapper Mapper[T]) *Var[T] {
+	v := &Var[T]{
+		spec:         Spec{Key: key, Type: typeName[T](), Description: description, Default: formatDefault(defaultValue), Secret: isSecret(defaultValue)},
+		defaultValue: defaultValue,
+		getDefault:   OrReturn(defaultValue),
+		mapper:       mapper,
+	}
+	defaultRegistry.Declare(v.spec)
+	return v
+}
+
+// Require declares a variable that must be set, and adds it to the default Registry
+func Require[T any](key string, description string, mapper Mapper[T]) *Var[T] {
+	var zero T
+	v := &Var[T]{
+		spec:       Spec{Key: key, Type: typeName[T](), Description: description, Required: true, Secret: isSecret(zero)},
+		getDefault: Required[T],
+		mapper:     mapper,
+	}
+	defaultRegistry.Declare(v.spec)
+	return v
+}
+
+// StringVar declares a string variable, see Define
+func StringVar(key string, defaultValue string, description string) *Var[string] {
+	return Define(key, defaultValue, description, String)
+}
+
+// IntVar declares an int variable, see Define
+func IntVar(key string, defaultValue int, description string) *Var[int] {
+	return Define(key, defaultValue, description, Int)
+}
+
+// Int64Var declares an int64 variable, see Define
+func Int64Var(key string, defaultValue int64, description string) *Var[int64] {
+	return Define(key, defaultValue, description, Int64)
+}
+
+// UintVar declares a uint variable, see Define
+func UintVar(key string, defaultValue uint, description string) *Var[uint] {
+	return Define(key, defaultValue, description, Uint)
+}
+
+// Float64Var declares a float64 variable, see Define
+func Float64Var(key string, defaultValue float64, description string) *Var[float64] {
+	return Define(key, defaultValue, description, Float64)
+}
+
+// BoolVar declares a bool variable, see Define
+func BoolVar(key string, defaultValue bool, description string) *Var[bool] {
+	return Define(key, defaultValue, description, Bool)
+}
+
+// DurationVar declares a time.Duration variable, see Define
+func DurationVar(key string, defaultValue time.Duration, description string) *Var[time.Duration] {
+	return Define(key, defaultValue, description, Duration)
+}
+
+// Get returns the value of the variable, reading it from the default Env the first time it is called
+func (v *Var[T]) Get() (T, error) {
+	env := Default()
+	version := env.cache.version()
+	v.mu.RLock()
+	if v.fresh(env, version) {
+		defer v.mu.RUnlock()
+		return v.value, v.err
+	}
+	v.mu.RUnlock()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if !v.fresh(env, version) {
+		v.value, v.err = GetFrom(env, v.spec.Key, v.getDefault, v.mapper)
+		v.resolved, v.env, v.version = true, env, version
+	}
+	return v.value, v.err
+}
+
+// fresh tells whether the memoized value was read from the given version of the Env
+func (v *Var[T]) fresh(env *Env, version uint64) bool {
+	return v.resolved && v.env == env && v.version == version
+}
+
+// MustGet returns the value of the variable or panics with the error Get would have returned
+func (v *Var[T]) MustGet() T {
+	value, err := v.Get()
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// WithDefaultText sets the default as it is documented in the default Registry, for defaults whose format depends on the mapper,
+// such as WithDefaultText("a;b") for a slice read with SliceOf(";", String). It returns the Var, so that it can be chained to Define
+func (v *Var[T]) WithDefaultText(text string) *Var[T] {
+	v.spec.Default = text
+	defaultRegistry.Declare(v.spec)
+	return v
+}
+
+// Key returns the key of the variable
+func (v *Var[T]) Key() string {
+	return v.spec.Key
+}
+
+// Default returns the value used when the variable is not set, which is the zero value for required variables
+func (v *Var[T]) Default() T {
+	return v.defaultValue
+}
+
+// Description returns the description of the variable
+func (v *Var[T]) Description() string {
+	return v.spec.Description
+}
+
+// Spec returns the declaration of the variable as it was added to the default Registry
+func (v *Var[T]) Spec() Spec {
+	return v.spec
+}
+
+func typeName[T any]() string {
+	var zero T
+	return reflect.TypeOf(&zero).Elem().String()
+}
+
+// formatDefault writes a default value as it would be set in the environment. Slices, maps, structs and pointers are left out,
+// as their format depends on the mapper, see Var.WithDefaultText
+func formatDefault(value any) string {
+	if isSecret(value) {
+		return redacted
+	}
+	if marshaler, ok := value.(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		if err == nil {
+			return string(text)
+		}
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(value)
+	}
+	return ""
+}
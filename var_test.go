@@ -0,0 +1,142 @@
+package lazyenv_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danielkov/lazyenv"
+)
+
+func TestVar(t *testing.T) {
+	port := lazyenv.IntVar("TEST_VAR_PORT", 8080, "HTTP port")
+	lazyenv.SetSource(lazyenv.Map{"TEST_VAR_PORT": "9090"})
+	defer lazyenv.SetSource(lazyenv.OS)
+	if value := port.MustGet(); value != 9090 {
+		t.Errorf("expected 9090, got %d", value)
+	}
+	lazyenv.SetSource(lazyenv.Map{})
+	if value := port.MustGet(); value != 8080 {
+		t.Errorf("expected the default after replacing the source, got %d", value)
+	}
+}
+
+func TestVar_Memoized(t *testing.T) {
+	source := lazyenv.Map{"TEST_VAR_HOSTS": "a,b"}
+	calls := 0
+	hosts := lazyenv.Define("TEST_VAR_HOSTS", []string{"localhost"}, "Hosts to connect to", func(value string) ([]string, error) {
+		calls++
+		return lazyenv.SliceOf(",", lazyenv.String)(value)
+	})
+	lazyenv.SetSource(source)
+	defer lazyenv.SetSource(lazyenv.OS)
+	hosts.MustGet()
+	hosts.MustGet()
+	if calls != 1 {
+		t.Errorf("expected the value to be parsed once, got %d calls", calls)
+	}
+	source["TEST_VAR_HOSTS"] = "c"
+	lazyenv.Reset()
+	if value := hosts.MustGet(); len(value) != 1 || value[0] != "c" {
+		t.Errorf("expected the value to be read again after Reset, got %v", value)
+	}
+	if calls != 2 {
+		t.Errorf("expected the value to be parsed again after Reset, got %d calls", calls)
+	}
+}
+
+func TestVar_Require(t *testing.T) {
+	token := lazyenv.Require("TEST_VAR_TOKEN", "API token", lazyenv.SecretOf(lazyenv.String))
+	lazyenv.SetSource(lazyenv.Map{})
+	defer lazyenv.SetSource(lazyenv.OS)
+	if _, err := token.Get(); !errors.Is(err, lazyenv.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	spec := token.Spec()
+	if !spec.Required || !spec.Secret || spec.Type != "lazyenv.Secret[string]" {
+		t.Errorf("expected a required secret, got %+v", spec)
+	}
+}
+
+func declared(key string) (lazyenv.Spec, bool) {
+	for _, spec := range lazyenv.DefaultRegistry().Specs() {
+		if spec.Key == key {
+			return spec, true
+		}
+	}
+	return lazyenv.Spec{}, false
+}
+
+func TestVar_Metadata(t *testing.T) {
+	port := lazyenv.DurationVar("TEST_VAR_METADATA", 30*time.Second, "Timeout")
+	if port.Key() != "TEST_VAR_METADATA" || port.Description() != "Timeout" || port.Default() != 30*time.Second {
+		t.Errorf("unexpected metadata %s %s %v", port.Key(), port.Description(), port.Default())
+	}
+	spec, ok := declared("TEST_VAR_METADATA")
+	if !ok {
+		t.Fatal("expected TEST_VAR_METADATA to be declared in the default registry")
+	}
+	if spec.Type != "time.Duration" || spec.Default != "30s" || spec.Required {
+		t.Errorf("unexpected spec %+v", spec)
+	}
+}
+
+func TestVar_DefaultText(t *testing.T) {
+	hosts := lazyenv.Define("TEST_VAR_DEFAULT_TEXT", []string{"a", "b"}, "Hosts", lazyenv.SliceOf(";", lazyenv.String))
+	if spec, _ := declared("TEST_VAR_DEFAULT_TEXT"); spec.Default != "" {
+		t.Errorf("expected a slice default to be left out, got %q", spec.Default)
+	}
+	hosts.WithDefaultText("a;b")
+	spec, _ := declared("TEST_VAR_DEFAULT_TEXT")
+	if spec.Default != "a;b" || hosts.Spec().Default != "a;b" {
+		t.Fatalf("expected the default text to be declared, got %+v", spec)
+	}
+	registry := lazyenv.NewRegistry()
+	registry.Declare(spec)
+	var b strings.Builder
+	if err := registry.WriteDotenv(&b); err != nil {
+		t.Fatal(err)
+	}
+	values, err := lazyenv.ParseDotenv(strings.NewReader(b.String()), ".env.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := lazyenv.New(lazyenv.WithSource(values))
+	if value := lazyenv.MustGetFrom(env, "TEST_VAR_DEFAULT_TEXT", lazyenv.SliceOf(";", lazyenv.String)); len(value) != 2 || value[1] != "b" {
+		t.Errorf("expected the .env.example default to read back as [a b], got %v", value)
+	}
+}
+
+func TestVar_Concurrent(t *testing.T) {
+	port := lazyenv.IntVar("TEST_VAR_CONCURRENT", 8080, "HTTP port")
+	lazyenv.SetSource(lazyenv.Map{"TEST_VAR_CONCURRENT": "9090"})
+	defer lazyenv.SetSource(lazyenv.OS)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if i == 0 && j%20 == 0 {
+					lazyenv.Reset()
+				}
+				if value := port.MustGet(); value != 9090 {
+					t.Errorf("expected 9090, got %d", value)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func BenchmarkVar_Get(b *testing.B) {
+	port := lazyenv.IntVar("BENCHMARK_VAR_PORT", 8080, "HTTP port")
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			port.MustGet()
+		}
+	})
+}
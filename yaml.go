@@ -0,0 +1,294 @@
+package lazyenv
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseYAML reads a YAML document from r and flattens it into env style keys, see Flatten. name is only used to report errors.
+// Only a subset of YAML is supported: block mappings and sequences indented with spaces, plain, single and double quoted scalars,
+// flow sequences of scalars and comments. Anchors, tags, block scalars and multiple documents are not supported
+func ParseYAML(r io.Reader, name string, separator string) (Map, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := yamlParser{name: name}
+	if err := p.tokenize(string(content)); err != nil {
+		return nil, err
+	}
+	if len(p.lines) == 0 {
+		return Map{}, nil
+	}
+	if p.lines[0].indent != 0 || isYAMLSequenceItem(p.lines[0].text) {
+		return nil, p.errorf(p.lines[0], "expected a mapping at the top level")
+	}
+	document, err := p.parseMapping(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, p.errorf(p.lines[p.pos], "unexpected indentation")
+	}
+	return flattenFile(name, document, separator)
+}
+
+// LoadYAML reads and flattens the YAML file at path
+func LoadYAML(path string, separator string) (Map, error) {
+	return loadConfig(path, separator, ParseYAML)
+}
+
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+type yamlParser struct {
+	name  string
+	lines []yamlLine
+	pos   int
+}
+
+func (p *yamlParser) errorf(line yamlLine, format string, args ...any) error {
+	return &SyntaxError{p.name, line.number, fmt.Sprintf(format, args...)}
+}
+
+// tokenize splits the document into lines with their indentation, leaving out blank lines, comments and document markers
+func (p *yamlParser) tokenize(content string) error {
+	for i, text := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line := yamlLine{number: i + 1}
+		trimmed := strings.TrimLeft(text, " ")
+		line.indent = len(text) - len(trimmed)
+		line.text = strings.TrimSpace(stripYAMLComment(trimmed))
+		if line.text == "" || line.text == "---" || line.text == "..." {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return p.errorf(line, "tabs cannot be used for indentation")
+		}
+		if strings.HasPrefix(line.text, "%") || strings.HasPrefix(line.text, "---") {
+			return p.errorf(line, "directives and multiple documents are not supported")
+		}
+		p.lines = append(p.lines, line)
+	}
+	return nil
+}
+
+func (p *yamlParser) parseBlock(indent int) (any, error) {
+	if isYAMLSequenceItem(p.lines[p.pos].text) {
+		return p.parseSequence(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+func (p *yamlParser) parseMapping(indent int) (map[string]any, error) {
+	mapping := make(map[string]any)
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf(line, "unexpected indentation")
+		}
+		if isYAMLSequenceItem(line.text) {
+			return nil, p.errorf(line, "expected a key, found a sequence item")
+		}
+		key, rest, err := splitYAMLKey(line.text)
+		if err != nil {
+			return nil, p.errorf(line, "%v", err)
+		}
+		if _, exists := mapping[key]; exists {
+			return nil, p.errorf(line, "duplicate key %q", key)
+		}
+		p.pos++
+		if rest != "" {
+			value, err := parseYAMLScalar(rest)
+			if err != nil {
+				return nil, p.errorf(line, "%v", err)
+			}
+			mapping[key] = value
+			continue
+		}
+		mapping[key] = nil
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			// sequences are allowed at the same indentation as their key
+			if next.indent > indent || (next.indent == indent && isYAMLSequenceItem(next.text)) {
+				value, err := p.parseBlock(next.indent)
+				if err != nil {
+					return nil, err
+				}
+				mapping[key] = value
+			}
+		}
+	}
+	return mapping, nil
+}
+
+func (p *yamlParser) parseSequence(indent int) ([]any, error) {
+	var sequence []any
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent || (line.indent == indent && !isYAMLSequenceItem(line.text)) {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf(line, "unexpected indentation")
+		}
+		item := strings.TrimLeft(line.text[1:], " ")
+		if item == "" {
+			p.pos++
+			if p.pos == len(p.lines) || p.lines[p.pos].indent <= indent {
+				sequence = append(sequence, nil)
+				continue
+			}
+			value, err := p.parseBlock(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			sequence = append(sequence, value)
+			continue
+		}
+		if _, _, err := splitYAMLKey(item); err == nil || isYAMLSequenceItem(item) {
+			// the item starts a nested block, parse it as if it was on its own line
+			p.lines[p.pos] = yamlLine{line.number, line.indent + len(line.text) - len(item), item}
+			value, err := p.parseBlock(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			sequence = append(sequence, value)
+			continue
+		}
+		value, err := parseYAMLScalar(item)
+		if err != nil {
+			return nil, p.errorf(line, "%v", err)
+		}
+		sequence = append(sequence, value)
+		p.pos++
+	}
+	return sequence, nil
+}
+
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitYAMLKey splits "key: value" on the first colon outside quotes that is followed by a space or the end of the line
+func splitYAMLKey(text string) (string, string, error) {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && i == 0:
+			quote = c
+		case c == ':' && (i == len(text)-1 || text[i+1] == ' '):
+			key, err := parseYAMLScalar(strings.TrimSpace(text[:i]))
+			if err != nil {
+				return "", "", err
+			}
+			name, ok := key.(string)
+			if !ok || name == "" {
+				return "", "", fmt.Errorf("invalid key %q", text[:i])
+			}
+			return name, strings.TrimSpace(text[i+1:]), nil
+		}
+	}
+	return "", "", fmt.Errorf("expected \"key: value\", found %q", text)
+}
+
+// parseYAMLScalar parses a scalar or a flow sequence of scalars, returning nil for null
+func parseYAMLScalar(text string) (any, error) {
+	switch {
+	case text == "" || text == "~" || text == "null" || text == "Null" || text == "NULL":
+		return nil, nil
+	case text == "{}":
+		return map[string]any{}, nil
+	case text[0] == '"':
+		if len(text) < 2 || closingQuote(text[1:], '"') != len(text)-2 {
+			return nil, fmt.Errorf("invalid double quoted scalar %s", text)
+		}
+		return unescapeDotenv(text[1 : len(text)-1]), nil
+	case text[0] == '\'':
+		if len(text) < 2 || text[len(text)-1] != '\'' || strings.Contains(strings.ReplaceAll(text[1:len(text)-1], "''", ""), "'") {
+			return nil, fmt.Errorf("invalid single quoted scalar %s", text)
+		}
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	case text[0] == '[':
+		if text[len(text)-1] != ']' {
+			return nil, fmt.Errorf("invalid flow sequence %s", text)
+		}
+		var items []any
+		for _, item := range splitOutsideQuotes(text[1:len(text)-1], ',') {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			if item[0] == '[' || item[0] == '{' {
+				return nil, fmt.Errorf("nested flow collections are not supported")
+			}
+			value, err := parseYAMLScalar(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+	case strings.ContainsAny(text[:1], "{&*!|>@`"):
+		return nil, fmt.Errorf("unsupported YAML syntax %s", text)
+	}
+	return text, nil
+}
+
+// stripYAMLComment removes a # comment that is at the start of the text or preceded by a space, outside quotes
+func stripYAMLComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if (c == '\\' && quote == '"') || (c == '\'' && quote == '\'' && i+1 < len(text) && text[i+1] == '\'') {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || text[i-1] == ' ' || text[i-1] == '[' || text[i-1] == ',' {
+				quote = c
+			}
+		case c == '#' && (i == 0 || text[i-1] == ' '):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// splitOutsideQuotes splits text on separator, ignoring separators inside single or double quotes
+func splitOutsideQuotes(text string, separator byte) []string {
+	var parts []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == separator:
+			parts = append(parts, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, text[start:])
+}
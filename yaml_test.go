@@ -0,0 +1,77 @@
+package lazyenv_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/danielkov/lazyenv"
+)
+
+const yamlFixture = `# service configuration
+---
+name: app # inline comment
+empty:
+nothing: ~
+quoted: "a # b: c\n"
+single: 'it''s'
+url: http://localhost:8080/path
+database:
+  host: localhost
+  pool-size: 5
+  replicas:
+    - db1
+    - "db2"
+ports: [80, 443]
+labels:
+- team
+- billing
+servers:
+  - host: a
+    port: 1
+  - host: b
+    port: 2
+`
+
+func TestYAML(t *testing.T) {
+	t.Parallel()
+	values, err := lazyenv.ParseYAML(strings.NewReader(yamlFixture), "config.yaml", "_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectValues(t, values, map[string]string{
+		"NAME":               "app",
+		"QUOTED":             "a # b: c\n",
+		"SINGLE":             "it's",
+		"URL":                "http://localhost:8080/path",
+		"DATABASE_HOST":      "localhost",
+		"DATABASE_POOL_SIZE": "5",
+		"DATABASE_REPLICAS":  "db1,db2",
+		"PORTS":              "80,443",
+		"LABELS":             "team,billing",
+		"SERVERS":            `[{"host":"a","port":"1"},{"host":"b","port":"2"}]`,
+	})
+}
+
+func TestYAML_Errors(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		input string
+		line  int
+	}{
+		{"a: 1\n  b: 2\n", 2},
+		{"a: 1\nnot a mapping\n", 2},
+		{"a: 1\na: 2\n", 2},
+		{"a:\n\tb: 1\n", 2},
+		{"a: |\n  text\n", 1},
+		{"a: &anchor 1\n", 1},
+		{"- a\n", 1},
+	}
+	for _, c := range cases {
+		_, err := lazyenv.ParseYAML(strings.NewReader(c.input), "config.yaml", "_")
+		var syntaxErr *lazyenv.SyntaxError
+		if !errors.As(err, &syntaxErr) || syntaxErr.Line != c.line {
+			t.Errorf("expected a syntax error on line %d for %q, got %v", c.line, c.input, err)
+		}
+	}
+}